@@ -0,0 +1,24 @@
+package otadmin
+
+import (
+	glib "github.com/ostreedev/ostree-go/pkg/glibobject"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "otadmin.go.h"
+import "C"
+
+// Cleanup prunes rollback deployments beyond the sysroot's retention
+// policy and deletes no-longer-referenced objects from the repo, mirroring
+// `ostree admin cleanup`.
+func (s *Sysroot) Cleanup(cancellable *glib.GCancellable) error {
+	var cerr *C.GError
+	r := C.ostree_sysroot_cleanup(s.native(), cCancellable(cancellable), &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
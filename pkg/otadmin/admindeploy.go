@@ -0,0 +1,80 @@
+package otadmin
+
+import (
+	"unsafe"
+
+	glib "github.com/ostreedev/ostree-go/pkg/glibobject"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "otadmin.go.h"
+import "C"
+
+// Deployment represents one entry in the sysroot's bootable deployment
+// list, as returned by Status.
+type Deployment struct {
+	ptr unsafe.Pointer
+}
+
+func (d *Deployment) native() *C.OstreeDeployment {
+	if d == nil || d.ptr == nil {
+		return nil
+	}
+	return (*C.OstreeDeployment)(d.ptr)
+}
+
+func deploymentFromNative(od *C.OstreeDeployment) *Deployment {
+	if od == nil {
+		return nil
+	}
+	return &Deployment{unsafe.Pointer(od)}
+}
+
+// Deploy stages a new deployment for osname at the given commit revision,
+// using origin as the refspec/kargs origin keyfile (see Deployment.SetOrigin) and
+// kargs as the kernel argument list, then writes it out and prunes old
+// rollback targets per the sysroot's retention policy.
+func (s *Sysroot) Deploy(osname, revision string, origin *Origin, kargs []string, cancellable *glib.GCancellable) (*Deployment, error) {
+	cosname := C.CString(osname)
+	defer C.free(unsafe.Pointer(cosname))
+	crevision := C.CString(revision)
+	defer C.free(unsafe.Pointer(crevision))
+
+	var ckargs **C.char
+	if len(kargs) > 0 {
+		ckargsSlice := make([]*C.char, len(kargs)+1)
+		for i, k := range kargs {
+			ckargsSlice[i] = C.CString(k)
+		}
+		defer func() {
+			for _, k := range ckargsSlice {
+				if k != nil {
+					C.free(unsafe.Pointer(k))
+				}
+			}
+		}()
+		ckargs = (**C.char)(unsafe.Pointer(&ckargsSlice[0]))
+	}
+
+	var corigin *C.GKeyFile
+	if origin != nil {
+		corigin = origin.native()
+	}
+
+	var cdeployment *C.OstreeDeployment
+	var cerr *C.GError
+	r := C.ostree_sysroot_deploy_tree(s.native(), cosname, crevision, corigin, nil, ckargs, &cdeployment, cCancellable(cancellable), &cerr)
+	if !gobool(r) {
+		return nil, generateError(cerr)
+	}
+
+	r = C.ostree_sysroot_simple_write_deployment(s.native(), cosname, cdeployment, nil, 0, cCancellable(cancellable), &cerr)
+	if !gobool(r) {
+		return nil, generateError(cerr)
+	}
+
+	return deploymentFromNative(cdeployment), nil
+}
@@ -0,0 +1,73 @@
+package otadmin
+
+import (
+	"unsafe"
+
+	glib "github.com/ostreedev/ostree-go/pkg/glibobject"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "otadmin.go.h"
+import "C"
+
+// Diff compares the on-disk checkouts of two deployments and reports the
+// paths that were modified, removed, and added going from a to b,
+// mirroring `ostree admin diff`.
+func (s *Sysroot) Diff(a, b *Deployment, cancellable *glib.GCancellable) (modified, removed, added []string, err error) {
+	dirA := C.ostree_sysroot_get_deployment_directory(s.native(), a.native())
+	defer C.g_object_unref(C.gpointer(dirA))
+	dirB := C.ostree_sysroot_get_deployment_directory(s.native(), b.native())
+	defer C.g_object_unref(C.gpointer(dirB))
+
+	cmodified := C.g_ptr_array_new()
+	defer C.g_ptr_array_unref(cmodified)
+	cremoved := C.g_ptr_array_new()
+	defer C.g_ptr_array_unref(cremoved)
+	cadded := C.g_ptr_array_new()
+	defer C.g_ptr_array_unref(cadded)
+
+	var cerr *C.GError
+	r := C.ostree_diff_dirs(0, dirA, dirB, cmodified, cremoved, cadded, cCancellable(cancellable), &cerr)
+	if !gobool(r) {
+		return nil, nil, nil, generateError(cerr)
+	}
+
+	modified = diffItemPaths(cmodified)
+	removed = gfilePaths(cremoved)
+	added = gfilePaths(cadded)
+	return modified, removed, added, nil
+}
+
+func diffItemPaths(arr *C.GPtrArray) []string {
+	n := int(arr.len)
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		item := (*C.OstreeDiffItem)(C.g_ptr_array_index(arr, C.guint(i)))
+		if item == nil || item.src == nil {
+			continue
+		}
+		cpath := C.g_file_get_path(item.src)
+		if cpath != nil {
+			out = append(out, C.GoString(cpath))
+			C.free(unsafe.Pointer(cpath))
+		}
+	}
+	return out
+}
+
+func gfilePaths(arr *C.GPtrArray) []string {
+	n := int(arr.len)
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		f := (*C.GFile)(C.g_ptr_array_index(arr, C.guint(i)))
+		cpath := C.g_file_get_path(f)
+		if cpath != nil {
+			out = append(out, C.GoString(cpath))
+			C.free(unsafe.Pointer(cpath))
+		}
+	}
+	return out
+}
@@ -0,0 +1,24 @@
+package otadmin
+
+import (
+	glib "github.com/ostreedev/ostree-go/pkg/glibobject"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "otadmin.go.h"
+import "C"
+
+// Init lays out a fresh sysroot at s's path: the `ostree/repo` and
+// `ostree/deploy` directories, with an empty repo ready for OSInit to
+// populate with a stateroot.
+func Init(s *Sysroot, cancellable *glib.GCancellable) error {
+	var cerr *C.GError
+	r := C.ostree_sysroot_ensure_initialized(s.native(), cCancellable(cancellable), &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
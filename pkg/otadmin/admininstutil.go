@@ -0,0 +1,51 @@
+package otadmin
+
+import (
+	"unsafe"
+
+	glib "github.com/ostreedev/ostree-go/pkg/glibobject"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "otadmin.go.h"
+import "C"
+
+// InstUtilSetKernelArgs replaces deployment's kernel argument list and
+// rewrites the sysroot's deployment set to persist it, mirroring the
+// lower-level `ostree admin instutil set-kargs` helper used by bootloader
+// installers.
+func (s *Sysroot) InstUtilSetKernelArgs(deployment *Deployment, kargs []string, cancellable *glib.GCancellable) error {
+	ckargs := make([]*C.char, len(kargs)+1)
+	for i, k := range kargs {
+		ckargs[i] = C.CString(k)
+	}
+	defer func() {
+		for _, k := range ckargs {
+			if k != nil {
+				C.free(unsafe.Pointer(k))
+			}
+		}
+	}()
+
+	bootconfig := C.ostree_deployment_get_bootconfig(deployment.native())
+	joined := C.g_strjoinv(C.CString(" "), (**C.char)(unsafe.Pointer(&ckargs[0])))
+	defer C.free(unsafe.Pointer(joined))
+	C.ostree_bootconfig_parser_set(bootconfig, C.CString("options"), joined)
+
+	all := s.deploymentList()
+	allNative := C.g_ptr_array_new()
+	defer C.g_ptr_array_unref(allNative)
+	for _, d := range all {
+		C.g_ptr_array_add(allNative, C.gpointer(d.native()))
+	}
+
+	var cerr *C.GError
+	r := C.ostree_sysroot_write_deployments(s.native(), allNative, cCancellable(cancellable), &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package otadmin
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestSysroot shells out to the ostree CLI to initialize a fresh sysroot
+// and stateroot, skipping the test if ostree isn't installed or this
+// environment can't initialize one (e.g. no reflink/hardlink support for
+// bare-user mode), the same pattern otbuiltin's tests use for repos.
+func newTestSysroot(t *testing.T, osname string) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("ostree"); err != nil {
+		t.Skip("ostree CLI not installed")
+	}
+
+	sysrootPath := filepath.Join(t.TempDir(), "sysroot")
+	if err := os.MkdirAll(sysrootPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if out, err := exec.Command("ostree", "admin", "init-fs", sysrootPath).CombinedOutput(); err != nil {
+		t.Skipf("ostree admin init-fs unavailable in this environment: %v: %s", err, out)
+	}
+	if out, err := exec.Command("ostree", "admin", "os-init", "--sysroot="+sysrootPath, osname).CombinedOutput(); err != nil {
+		t.Skipf("ostree admin os-init unavailable in this environment: %v: %s", err, out)
+	}
+
+	return sysrootPath
+}
+
+// commitTestContent commits a one-file tree to branch in the repo at
+// repoPath via the ostree CLI, returning the resulting checksum.
+func commitTestContent(t *testing.T, repoPath, branch, content string) string {
+	t.Helper()
+
+	srcDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(srcDir, "file.txt"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := exec.Command("ostree", "--repo="+repoPath, "commit", "--branch="+branch, "-s", "test", srcDir).CombinedOutput()
+	if err != nil {
+		t.Fatalf("ostree commit: %v: %s", err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// TestDeployUpgradeUndeployRoundTrip exercises the Sysroot lifecycle a real
+// caller drives: deploy an initial commit, upgrade onto a newer one, then
+// undeploy it, checking Status after each step.
+func TestDeployUpgradeUndeployRoundTrip(t *testing.T) {
+	const osname = "testos"
+	const branch = "testbranch"
+
+	sysrootPath := newTestSysroot(t, osname)
+	repoPath := filepath.Join(sysrootPath, "ostree", "repo")
+
+	first := commitTestContent(t, repoPath, branch, "v1")
+
+	sysroot, err := NewSysroot(sysrootPath)
+	if err != nil {
+		t.Fatalf("NewSysroot: %v", err)
+	}
+	if err := sysroot.Load(nil); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	origin := OriginNewFromRefspec(branch)
+	if _, err := sysroot.Deploy(osname, first, origin, nil, nil); err != nil {
+		t.Fatalf("Deploy: %v", err)
+	}
+
+	if err := sysroot.Load(nil); err != nil {
+		t.Fatalf("reloading sysroot after Deploy: %v", err)
+	}
+	statuses := sysroot.Status()
+	if len(statuses) != 1 {
+		t.Fatalf("Status() returned %d deployments after Deploy, want 1", len(statuses))
+	}
+	if statuses[0].Checksum != first {
+		t.Fatalf("deployed checksum = %q, want %q", statuses[0].Checksum, first)
+	}
+
+	second := commitTestContent(t, repoPath, branch, "v2")
+
+	if _, err := sysroot.Upgrade(osname, UpgradeOptions{}, nil); err != nil {
+		t.Fatalf("Upgrade: %v", err)
+	}
+
+	if err := sysroot.Load(nil); err != nil {
+		t.Fatalf("reloading sysroot after Upgrade: %v", err)
+	}
+	statuses = sysroot.Status()
+	if len(statuses) == 0 || statuses[0].Checksum != second {
+		t.Fatalf("after Upgrade, deployments = %+v, want checksum %q", statuses, second)
+	}
+
+	if err := sysroot.Undeploy(0, nil); err != nil {
+		t.Fatalf("Undeploy: %v", err)
+	}
+
+	if err := sysroot.Load(nil); err != nil {
+		t.Fatalf("reloading sysroot after Undeploy: %v", err)
+	}
+	if statuses := sysroot.Status(); len(statuses) != 0 {
+		t.Fatalf("expected no deployments after Undeploy, got %+v", statuses)
+	}
+}
@@ -0,0 +1,29 @@
+package otadmin
+
+import (
+	"unsafe"
+
+	glib "github.com/ostreedev/ostree-go/pkg/glibobject"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "otadmin.go.h"
+import "C"
+
+// OSInit creates a new stateroot (osname) inside the sysroot, i.e. the
+// `ostree/deploy/<stateroot>` directory hierarchy that deployments for
+// that osname will live under.
+func OSInit(s *Sysroot, osname string, cancellable *glib.GCancellable) error {
+	cosname := C.CString(osname)
+	defer C.free(unsafe.Pointer(cosname))
+
+	var cerr *C.GError
+	r := C.ostree_sysroot_init_osname(s.native(), cosname, cCancellable(cancellable), &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package otadmin
+
+import (
+	"unsafe"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "otadmin.go.h"
+import "C"
+
+// Origin is the keyfile (`origin`) attached to a deployment describing
+// where it came from: its refspec and any Deploy-time kargs override.
+type Origin struct {
+	ptr unsafe.Pointer
+}
+
+func (o *Origin) native() *C.GKeyFile {
+	if o == nil || o.ptr == nil {
+		return nil
+	}
+	return (*C.GKeyFile)(o.ptr)
+}
+
+func originFromNative(kf *C.GKeyFile) *Origin {
+	if kf == nil {
+		return nil
+	}
+	return &Origin{unsafe.Pointer(kf)}
+}
+
+// OriginNewFromRefspec builds an Origin keyfile for the given refspec,
+// suitable for passing to Sysroot.Deploy.
+func OriginNewFromRefspec(refspec string) *Origin {
+	crefspec := C.CString(refspec)
+	defer C.free(unsafe.Pointer(crefspec))
+
+	kf := C.ostree_sysroot_origin_new_from_refspec(nil, crefspec)
+	return originFromNative(kf)
+}
+
+// GetOrigin returns the deployment's origin keyfile, or nil if it has
+// none.
+func (d *Deployment) GetOrigin() *Origin {
+	return originFromNative(C.ostree_deployment_get_origin(d.native()))
+}
+
+// SetOrigin replaces d's in-memory origin only; it does not write the
+// deployment's origin file. To deploy with a new origin, pass it to
+// Sysroot.Deploy.
+func (d *Deployment) SetOrigin(origin *Origin) {
+	C.ostree_deployment_set_origin(d.native(), origin.native())
+}
@@ -0,0 +1,163 @@
+package otadmin
+
+import (
+	"unsafe"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "otadmin.go.h"
+import "C"
+
+// DeploymentStatus summarizes one deployment for display or scripting,
+// the Go equivalent of a row from `ostree admin status`.
+type DeploymentStatus struct {
+	OSName   string
+	Checksum string
+	Version  string
+	Booted   bool
+	Refspec  string
+	Kargs    []string
+	Index    int
+}
+
+// deploymentList returns the sysroot's raw deployment list in boot order,
+// the default (most recently deployed) deployment first, the same order
+// `ostree admin status` prints.
+// It backs both Status and the lower-level admin helpers (e.g.
+// InstUtilSetKernelArgs) that need to rewrite the whole deployment set.
+func (s *Sysroot) deploymentList() []*Deployment {
+	all := C.ostree_sysroot_get_deployments(s.native())
+	if all == nil {
+		return nil
+	}
+	n := int(all.len)
+	deployments := make([]*Deployment, n)
+	for i := 0; i < n; i++ {
+		item := C.g_ptr_array_index(all, C.guint(i))
+		deployments[i] = deploymentFromNative((*C.OstreeDeployment)(item))
+	}
+	return deployments
+}
+
+// Status returns a DeploymentStatus for every deployment in the sysroot,
+// the Go equivalent of `ostree admin status`.
+func (s *Sysroot) Status() []DeploymentStatus {
+	booted := C.ostree_sysroot_get_booted_deployment(s.native())
+	repo := C.ostree_sysroot_repo(s.native())
+
+	deployments := s.deploymentList()
+	statuses := make([]DeploymentStatus, len(deployments))
+	for i, d := range deployments {
+		statuses[i] = describeDeployment(repo, booted, d, i)
+	}
+	return statuses
+}
+
+func describeDeployment(repo *C.OstreeRepo, booted *C.OstreeDeployment, d *Deployment, index int) DeploymentStatus {
+	osname := C.GoString(C.ostree_deployment_get_osname(d.native()))
+	checksum := C.GoString(C.ostree_deployment_get_csum(d.native()))
+
+	origin := C.ostree_deployment_get_origin(d.native())
+	var refspec string
+	if origin != nil {
+		groupC := C.CString("origin")
+		defer C.free(unsafe.Pointer(groupC))
+		keyC := C.CString("refspec")
+		defer C.free(unsafe.Pointer(keyC))
+		crefspec := C.g_key_file_get_string(origin, groupC, keyC, nil)
+		if crefspec != nil {
+			refspec = C.GoString(crefspec)
+			C.free(unsafe.Pointer(crefspec))
+		}
+	}
+
+	kargs := deploymentKargs(d)
+
+	return DeploymentStatus{
+		OSName:   osname,
+		Checksum: checksum,
+		Version:  commitVersion(repo, checksum),
+		Booted:   booted != nil && C.ostree_deployment_equal(C.gpointer(booted), C.gpointer(d.native())) != C.FALSE,
+		Refspec:  refspec,
+		Kargs:    kargs,
+		Index:    index,
+	}
+}
+
+// deploymentKargs reads a deployment's kernel argument list out of its
+// bootconfig's "options" line. There is no accessor that hands back an
+// OstreeKernelArgs* directly off an OstreeDeployment; the options string
+// has to be parsed via ostree_kernel_args_from_string first.
+func deploymentKargs(d *Deployment) []string {
+	bootconfig := C.ostree_deployment_get_bootconfig(d.native())
+	if bootconfig == nil {
+		return nil
+	}
+
+	optionsC := C.CString("options")
+	defer C.free(unsafe.Pointer(optionsC))
+	options := C.ostree_bootconfig_parser_get(bootconfig, optionsC)
+	if options == nil {
+		return nil
+	}
+
+	kargsPtrArray := C.ostree_kernel_args_from_string(options)
+	if kargsPtrArray == nil {
+		return nil
+	}
+	defer C.ostree_kernel_args_free(kargsPtrArray)
+
+	cstrs := C.ostree_kernel_args_to_strv(kargsPtrArray)
+	if cstrs == nil {
+		return nil
+	}
+	defer C.g_strfreev(cstrs)
+
+	var kargs []string
+	for p := cstrs; *p != nil; p = (**C.char)(unsafe.Pointer(uintptr(unsafe.Pointer(p)) + unsafe.Sizeof(*p))) {
+		kargs = append(kargs, C.GoString(*p))
+	}
+	return kargs
+}
+
+// commitVersion reads the "version" string libostree commits
+// conventionally stamp into their metadata (set via `ostree commit
+// --add-metadata-string=version=...`), returning "" if the commit has
+// none.
+func commitVersion(repo *C.OstreeRepo, checksum string) string {
+	if checksum == "" {
+		return ""
+	}
+
+	ccommit := C.CString(checksum)
+	defer C.free(unsafe.Pointer(ccommit))
+
+	var variant *C.GVariant
+	var cerr *C.GError
+	r := C.ostree_repo_load_variant(repo, C.OSTREE_OBJECT_TYPE_COMMIT, ccommit, &variant, &cerr)
+	if !gobool(r) {
+		return ""
+	}
+	defer C.g_variant_unref(variant)
+
+	metadata := C.g_variant_get_child_value(variant, 0)
+	defer C.g_variant_unref(metadata)
+
+	ckey := C.CString("version")
+	defer C.free(unsafe.Pointer(ckey))
+
+	value := C.g_variant_lookup_value(metadata, ckey, C._g_variant_type(C.CString("s")))
+	if value == nil {
+		return ""
+	}
+	defer C.g_variant_unref(value)
+
+	cstr := C.g_variant_get_string(value, nil)
+	if cstr == nil {
+		return ""
+	}
+	return C.GoString(cstr)
+}
@@ -0,0 +1,68 @@
+package otadmin
+
+import (
+	"unsafe"
+
+	glib "github.com/ostreedev/ostree-go/pkg/glibobject"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "otadmin.go.h"
+import "C"
+
+// Switch rebases osname onto a new remote/ref, pulling it if necessary and
+// deploying the result with a freshly built origin, mirroring
+// `ostree admin switch`.
+func (s *Sysroot) Switch(osname, remote, ref string, cancellable *glib.GCancellable) (*Deployment, error) {
+	refspec := ref
+	if remote != "" {
+		refspec = remote + ":" + ref
+	}
+
+	repo := C.ostree_sysroot_repo(s.native())
+
+	cremoteName := C.CString(remote)
+	defer C.free(unsafe.Pointer(cremoteName))
+
+	var cerr *C.GError
+	if remote != "" {
+		builder := C.g_variant_builder_new(C._g_variant_type(C.CString("a{sv}")))
+		crefs := make([]*C.gchar, 1)
+		crefs[0] = (*C.gchar)(C.CString(ref))
+		v := C.g_variant_new_strv((**C.gchar)(&crefs[0]), (C.gssize)(1))
+		C.free(unsafe.Pointer(crefs[0]))
+		k := C.CString("refs")
+		defer C.free(unsafe.Pointer(k))
+		C._g_variant_builder_add_twoargs(builder, C.CString("{sv}"), k, v)
+		coptions := C.g_variant_builder_end(builder)
+
+		r := C.ostree_repo_pull_with_options(repo, cremoteName, coptions, nil, cCancellable(cancellable), &cerr)
+		if !gobool(r) {
+			return nil, generateError(cerr)
+		}
+	}
+
+	// A pulled ref lives under the remote's namespace, so once a remote
+	// was pulled the locally resolvable name is the full refspec
+	// ("remote:ref"), not the bare ref.
+	resolveRef := refspec
+	if remote == "" {
+		resolveRef = ref
+	}
+
+	cresolveRef := C.CString(resolveRef)
+	defer C.free(unsafe.Pointer(cresolveRef))
+	var ccsum *C.char
+	r := C.ostree_repo_resolve_rev(repo, cresolveRef, C.FALSE, &ccsum, &cerr)
+	if !gobool(r) {
+		return nil, generateError(cerr)
+	}
+	defer C.free(unsafe.Pointer(ccsum))
+	csum := C.GoString(ccsum)
+
+	origin := OriginNewFromRefspec(refspec)
+	return s.Deploy(osname, csum, origin, nil, cancellable)
+}
@@ -0,0 +1,44 @@
+package otadmin
+
+import (
+	"errors"
+
+	glib "github.com/ostreedev/ostree-go/pkg/glibobject"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "otadmin.go.h"
+import "C"
+
+// Undeploy removes the deployment at the given index (0 is the default,
+// most recently deployed one, per the boot ordering Status returns) and
+// rewrites the boot configuration with it gone.
+func (s *Sysroot) Undeploy(index int, cancellable *glib.GCancellable) error {
+	all := C.ostree_sysroot_get_deployments(s.native())
+	if all == nil {
+		return errors.New("otadmin: sysroot not loaded")
+	}
+	n := int(all.len)
+	if index < 0 || index >= n {
+		return errors.New("otadmin: deployment index out of range")
+	}
+
+	kept := C.g_ptr_array_new()
+	for i := 0; i < n; i++ {
+		if i == index {
+			continue
+		}
+		item := C.g_ptr_array_index(all, C.guint(i))
+		C.g_ptr_array_add(kept, item)
+	}
+
+	var cerr *C.GError
+	r := C.ostree_sysroot_write_deployments(s.native(), kept, cCancellable(cancellable), &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package otadmin
+
+import (
+	glib "github.com/ostreedev/ostree-go/pkg/glibobject"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "otadmin.go.h"
+import "C"
+
+// LockMode selects how a deployment's `/usr` is made writable by
+// UnlockDeployment.
+type LockMode int
+
+const (
+	// LockModeNone leaves the deployment read-only.
+	LockModeNone LockMode = iota
+	// LockModeHotfix overlays a writable directory on `/usr` whose
+	// changes persist across reboots but are not carried into the next
+	// deployment (OSTREE_DEPLOYMENT_UNLOCKED_HOTFIX).
+	LockModeHotfix
+	// LockModeReplace overlays a transient writable directory on `/usr`
+	// whose changes are discarded on reboot
+	// (OSTREE_DEPLOYMENT_UNLOCKED_TRANSIENT).
+	LockModeReplace
+)
+
+func (m LockMode) native() C.OstreeDeploymentUnlockedState {
+	switch m {
+	case LockModeHotfix:
+		return C.OSTREE_DEPLOYMENT_UNLOCKED_HOTFIX
+	case LockModeReplace:
+		return C.OSTREE_DEPLOYMENT_UNLOCKED_TRANSIENT
+	default:
+		return C.OSTREE_DEPLOYMENT_UNLOCKED_NONE
+	}
+}
+
+// UnlockDeployment makes the sysroot's currently booted deployment
+// writable per mode, mirroring `ostree admin unlock`.
+func (s *Sysroot) UnlockDeployment(mode LockMode, cancellable *glib.GCancellable) error {
+	var cerr *C.GError
+	r := C.ostree_sysroot_deployment_unlock(s.native(), C.ostree_sysroot_get_booted_deployment(s.native()), mode.native(), cCancellable(cancellable), &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
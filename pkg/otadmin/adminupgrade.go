@@ -0,0 +1,198 @@
+package otadmin
+
+import (
+	"errors"
+	"unsafe"
+
+	glib "github.com/ostreedev/ostree-go/pkg/glibobject"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "otadmin.go.h"
+import "C"
+
+// UpgradeOptions controls the behaviour of Upgrade.
+type UpgradeOptions struct {
+	// AllowDowngrade permits deploying a commit that is not a descendant
+	// of the currently booted one. When false, Upgrade walks the new
+	// commit's parent chain looking for the current commit; if it isn't
+	// found (the new commit is not a descendant), Upgrade returns an
+	// error instead of deploying it.
+	AllowDowngrade bool
+	// OverrideRemote pulls from a remote other than the one in the
+	// deployment's origin.
+	OverrideRemote string
+}
+
+// maxAncestryWalk bounds how many parents isDescendantOf will follow
+// before giving up and falling back to a timestamp comparison; ostree
+// histories are normally shallow pull depths, so this comfortably covers
+// any realistic upgrade.
+const maxAncestryWalk = 10000
+
+// isDescendantOf reports whether commit's ancestry includes ancestor,
+// walking the parent chain recorded in each commit object. If the walk
+// exceeds maxAncestryWalk without finding ancestor or reaching the root,
+// it falls back to comparing commit timestamps.
+func isDescendantOf(repo *C.OstreeRepo, commit, ancestor string) (bool, error) {
+	current := commit
+	for i := 0; i < maxAncestryWalk; i++ {
+		if current == ancestor {
+			return true, nil
+		}
+
+		ccurrent := C.CString(current)
+		var variant *C.GVariant
+		var cerr *C.GError
+		r := C.ostree_repo_load_variant(repo, C.OSTREE_OBJECT_TYPE_COMMIT, ccurrent, &variant, &cerr)
+		C.free(unsafe.Pointer(ccurrent))
+		if !gobool(r) {
+			return false, generateError(cerr)
+		}
+
+		parentC := C.ostree_commit_get_parent(variant)
+		C.g_variant_unref(variant)
+		if parentC == nil {
+			return false, nil
+		}
+		current = C.GoString(parentC)
+		C.free(unsafe.Pointer(parentC))
+	}
+
+	return compareCommitTimestamps(repo, commit, ancestor)
+}
+
+// compareCommitTimestamps is the fallback ancestry heuristic: a commit
+// with a strictly later timestamp than ancestor is treated as "newer",
+// even though timestamps alone can't prove descendance.
+func compareCommitTimestamps(repo *C.OstreeRepo, commit, ancestor string) (bool, error) {
+	commitTime, err := commitTimestamp(repo, commit)
+	if err != nil {
+		return false, err
+	}
+	ancestorTime, err := commitTimestamp(repo, ancestor)
+	if err != nil {
+		return false, err
+	}
+	return commitTime > ancestorTime, nil
+}
+
+func commitTimestamp(repo *C.OstreeRepo, commit string) (uint64, error) {
+	ccommit := C.CString(commit)
+	defer C.free(unsafe.Pointer(ccommit))
+
+	var variant *C.GVariant
+	var cerr *C.GError
+	r := C.ostree_repo_load_variant(repo, C.OSTREE_OBJECT_TYPE_COMMIT, ccommit, &variant, &cerr)
+	if !gobool(r) {
+		return 0, generateError(cerr)
+	}
+	defer C.g_variant_unref(variant)
+
+	return uint64(C.ostree_commit_get_timestamp(variant)), nil
+}
+
+// Upgrade pulls the latest commit for osname's current origin refspec and,
+// if it differs from the running deployment, stages and writes it as a new
+// deployment. It returns the new Deployment, or nil if the system was
+// already up to date.
+func (s *Sysroot) Upgrade(osname string, opts UpgradeOptions, cancellable *glib.GCancellable) (*Deployment, error) {
+	cosname := C.CString(osname)
+	defer C.free(unsafe.Pointer(cosname))
+
+	current := C.ostree_sysroot_get_merge_deployment(s.native(), cosname)
+	if current == nil {
+		return nil, errors.New("otadmin: no deployment for osname " + osname)
+	}
+
+	origin := C.ostree_deployment_get_origin(current)
+	if origin == nil {
+		return nil, errors.New("otadmin: deployment has no origin")
+	}
+
+	groupC := C.CString("origin")
+	defer C.free(unsafe.Pointer(groupC))
+	keyC := C.CString("refspec")
+	defer C.free(unsafe.Pointer(keyC))
+	crefspec := C.g_key_file_get_string(origin, groupC, keyC, nil)
+	if crefspec == nil {
+		return nil, errors.New("otadmin: origin has no refspec")
+	}
+	defer C.free(unsafe.Pointer(crefspec))
+	refspec := C.GoString(crefspec)
+
+	var remoteName, ref string
+	var cremote, cref *C.char
+	r := C.ostree_parse_refspec(crefspec, &cremote, &cref, nil)
+	if !gobool(r) {
+		return nil, errors.New("otadmin: failed to parse refspec " + refspec)
+	}
+	if cremote != nil {
+		remoteName = C.GoString(cremote)
+		C.free(unsafe.Pointer(cremote))
+	}
+	ref = C.GoString(cref)
+	C.free(unsafe.Pointer(cref))
+
+	if opts.OverrideRemote != "" {
+		remoteName = opts.OverrideRemote
+	}
+
+	repo := C.ostree_sysroot_repo(s.native())
+
+	cremoteName := C.CString(remoteName)
+	defer C.free(unsafe.Pointer(cremoteName))
+
+	var cerr *C.GError
+	if remoteName != "" {
+		builder := C.g_variant_builder_new(C._g_variant_type(C.CString("a{sv}")))
+		crefs := make([]*C.gchar, 1)
+		crefs[0] = (*C.gchar)(C.CString(ref))
+		v := C.g_variant_new_strv((**C.gchar)(&crefs[0]), (C.gssize)(1))
+		C.free(unsafe.Pointer(crefs[0]))
+		k := C.CString("refs")
+		defer C.free(unsafe.Pointer(k))
+		C._g_variant_builder_add_twoargs(builder, C.CString("{sv}"), k, v)
+		coptions := C.g_variant_builder_end(builder)
+
+		ok := C.ostree_repo_pull_with_options(repo, cremoteName, coptions, nil, cCancellable(cancellable), &cerr)
+		if !gobool(ok) {
+			return nil, generateError(cerr)
+		}
+	}
+
+	resolveRef := refspec
+	if remoteName == "" {
+		resolveRef = ref
+	}
+
+	var newChecksum *C.char
+	cresolveRef := C.CString(resolveRef)
+	defer C.free(unsafe.Pointer(cresolveRef))
+	ok := C.ostree_repo_resolve_rev(repo, cresolveRef, C.TRUE, &newChecksum, &cerr)
+	if !gobool(ok) {
+		return nil, generateError(cerr)
+	}
+	defer C.free(unsafe.Pointer(newChecksum))
+	newRev := C.GoString(newChecksum)
+
+	currentChecksum := C.GoString(C.ostree_deployment_get_csum(current))
+	if newRev == currentChecksum {
+		return nil, nil
+	}
+
+	if !opts.AllowDowngrade {
+		descendant, err := isDescendantOf(repo, newRev, currentChecksum)
+		if err != nil {
+			return nil, err
+		}
+		if !descendant {
+			return nil, errors.New("otadmin: refusing to deploy " + newRev + ": not a descendant of " + currentChecksum + " (set AllowDowngrade to override)")
+		}
+	}
+
+	return s.Deploy(osname, newRev, originFromNative(origin), nil, cancellable)
+}
@@ -0,0 +1,90 @@
+package otadmin
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+import "C"
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func openTestRepo(t *testing.T, path string) *C.OstreeRepo {
+	t.Helper()
+
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	file := C.g_file_new_for_path(cpath)
+	defer C.g_object_unref(C.gpointer(file))
+
+	repo := C.ostree_repo_new(file)
+	var cerr *C.GError
+	if !gobool(C.ostree_repo_open(repo, nil, &cerr)) {
+		t.Fatalf("ostree_repo_open: %v", generateError(cerr))
+	}
+	return repo
+}
+
+func commitViaCLI(t *testing.T, repoPath, branch, dir string) string {
+	t.Helper()
+	out, err := exec.Command("ostree", "--repo="+repoPath, "commit", "--branch="+branch, "-s", "test", dir).CombinedOutput()
+	if err != nil {
+		t.Fatalf("ostree commit: %v: %s", err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// TestIsDescendantOf exercises the ancestry walk AllowDowngrade relies
+// on: a commit with an earlier parent in its history counts as a
+// descendant, and the reverse does not.
+func TestIsDescendantOf(t *testing.T) {
+	if _, err := exec.LookPath("ostree"); err != nil {
+		t.Skip("ostree CLI not installed")
+	}
+
+	dir := t.TempDir()
+	repoPath := filepath.Join(dir, "repo")
+	if out, err := exec.Command("ostree", "--repo="+repoPath, "init", "--mode=bare-user-only").CombinedOutput(); err != nil {
+		t.Fatalf("ostree init: %v: %s", err, out)
+	}
+
+	srcDir := filepath.Join(dir, "src")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(srcDir, "a.txt"), []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	first := commitViaCLI(t, repoPath, "test", srcDir)
+
+	if err := os.WriteFile(filepath.Join(srcDir, "a.txt"), []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	second := commitViaCLI(t, repoPath, "test", srcDir)
+
+	repo := openTestRepo(t, repoPath)
+	defer C.g_object_unref(C.gpointer(repo))
+
+	descendant, err := isDescendantOf(repo, second, first)
+	if err != nil {
+		t.Fatalf("isDescendantOf(second, first): %v", err)
+	}
+	if !descendant {
+		t.Fatal("expected the second commit to be a descendant of the first")
+	}
+
+	descendant, err = isDescendantOf(repo, first, second)
+	if err != nil {
+		t.Fatalf("isDescendantOf(first, second): %v", err)
+	}
+	if descendant {
+		t.Fatal("did not expect the first commit to be a descendant of the second")
+	}
+}
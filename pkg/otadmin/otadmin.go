@@ -0,0 +1,132 @@
+// Package otadmin wraps the OstreeSysroot/OstreeDeployment APIs, giving
+// Go programs the same deployment-management surface as the `ostree admin`
+// CLI: initializing a sysroot, deploying and upgrading an osname, switching
+// origins, and inspecting the current set of deployments.
+package otadmin
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	glib "github.com/ostreedev/ostree-go/pkg/glibobject"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "otadmin.go.h"
+import "C"
+
+// Sysroot represents a local ostree sysroot, i.e. the `/ostree` directory
+// tree containing deployments, the repo, and boot configuration.
+type Sysroot struct {
+	ptr unsafe.Pointer
+}
+
+// isInitialized reports whether the sysroot has a live native pointer.
+func (s *Sysroot) isInitialized() bool {
+	if s == nil || s.ptr == nil {
+		return false
+	}
+	return true
+}
+
+// native converts a Sysroot to its C equivalent.
+func (s *Sysroot) native() *C.OstreeSysroot {
+	if !s.isInitialized() {
+		return nil
+	}
+	return (*C.OstreeSysroot)(s.ptr)
+}
+
+// sysrootFromNative takes a C sysroot and wraps it in a Go struct.
+func sysrootFromNative(os *C.OstreeSysroot) *Sysroot {
+	if os == nil {
+		return nil
+	}
+	return &Sysroot{unsafe.Pointer(os)}
+}
+
+func cCancellable(c *glib.GCancellable) *C.GCancellable {
+	return (*C.GCancellable)(c.Ptr())
+}
+
+// NewSysroot creates a Sysroot object for the given path without touching
+// disk. Use Load (or Init for a brand new sysroot) to bring it up.
+func NewSysroot(path string) (*Sysroot, error) {
+	if path == "" {
+		return nil, errors.New("empty path")
+	}
+
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+
+	file := C.g_file_new_for_path(cpath)
+	defer C.g_object_unref(C.gpointer(file))
+
+	csysroot := C.ostree_sysroot_new(file)
+	return sysrootFromNative(csysroot), nil
+}
+
+// NewSysrootDefault creates a Sysroot wrapping the system's default sysroot
+// path ("/").
+func NewSysrootDefault() *Sysroot {
+	return sysrootFromNative(C.ostree_sysroot_new_default())
+}
+
+// Load opens the sysroot, parsing the deployment list and boot loader
+// configuration. It must be called (or Init, for a fresh sysroot) before
+// any other Sysroot method.
+func (s *Sysroot) Load(cancellable *glib.GCancellable) error {
+	var cerr *C.GError
+	r := C.ostree_sysroot_load(s.native(), cCancellable(cancellable), &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
+
+// Lock acquires the sysroot lock, preventing concurrent deployment changes
+// from another process.
+func (s *Sysroot) Lock() error {
+	var cerr *C.GError
+	r := C.ostree_sysroot_lock(s.native(), &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
+
+// Unlock releases a lock previously taken with Lock.
+func (s *Sysroot) Unlock() {
+	C.ostree_sysroot_unlock(s.native())
+}
+
+// Path returns the on-disk path of the sysroot.
+func (s *Sysroot) Path() string {
+	file := C.ostree_sysroot_get_path(s.native())
+	cpath := C.g_file_get_path(file)
+	defer C.free(unsafe.Pointer(cpath))
+	return C.GoString(cpath)
+}
+
+// generateError wraps a GLib error into a Go one.
+func generateError(err *C.GError) error {
+	if err == nil {
+		return errors.New("nil GError")
+	}
+
+	goErr := glib.ConvertGError(glib.ToGError(unsafe.Pointer(err)))
+	_, file, line, ok := runtime.Caller(1)
+	if ok {
+		return fmt.Errorf("%s:%d - %s", file, line, goErr)
+	}
+	return goErr
+}
+
+func gobool(b C.gboolean) bool {
+	return b != C.FALSE
+}
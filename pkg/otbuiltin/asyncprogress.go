@@ -0,0 +1,156 @@
+package otbuiltin
+
+import (
+	"sync"
+	"unsafe"
+
+	glib "github.com/ostreedev/ostree-go/pkg/glibobject"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "asyncprogress.go.h"
+import "C"
+
+// ProgressStatus is a snapshot of an OstreeAsyncProgress's standard pull
+// status keys, handed to the callback registered with NewAsyncProgress
+// every time the underlying GObject's "changed" signal fires.
+type ProgressStatus struct {
+	Status             string
+	OutstandingFetches uint
+	OutstandingWrites  uint
+	Fetched            uint
+	Requested          uint
+	BytesTransferred   uint64
+	StartTime          uint64
+	TotalDeltaParts    uint
+	FetchedDeltaParts  uint
+	ScannedMetadata    uint
+	MetadataFetched    uint
+}
+
+// PercentComplete reports how far the transfer has gotten as Fetched out
+// of Requested objects, in percent, capped at 100; it is 0 while nothing
+// has been requested yet.
+func (p ProgressStatus) PercentComplete() int {
+	if p.Requested == 0 {
+		return 0
+	}
+	pct := (p.Fetched * 100) / p.Requested
+	if pct > 100 {
+		pct = 100
+	}
+	return int(pct)
+}
+
+// BytesSec returns the average transfer rate since StartTime, or 0 if no
+// time has elapsed yet. libostree's "start-time" key is stamped from
+// g_get_monotonic_time(), an arbitrary (non-wall-clock) reference point,
+// so it has to be compared against that same clock rather than
+// time.Now().
+func (p ProgressStatus) BytesSec() uint64 {
+	if p.StartTime == 0 {
+		return 0
+	}
+	now := uint64(C.g_get_monotonic_time())
+	if now <= p.StartTime {
+		return 0
+	}
+	return p.BytesTransferred * 1000000 / (now - p.StartTime)
+}
+
+var (
+	progressHandlesMu sync.Mutex
+	progressHandles   = map[C.guintptr]func(ProgressStatus){}
+	progressObjects   = map[unsafe.Pointer]C.guintptr{}
+	progressHandleSeq C.guintptr
+)
+
+// progressKey* are allocated once at package init and reused for every
+// "changed" signal of every AsyncProgress, rather than CString'd fresh
+// (and leaked) on each invocation of a handler that can fire many times
+// a second during a pull.
+var (
+	progressKeyOutstandingFetches = C.CString("outstanding-fetches")
+	progressKeyOutstandingWrites  = C.CString("outstanding-writes")
+	progressKeyFetched            = C.CString("fetched")
+	progressKeyRequested          = C.CString("requested")
+	progressKeyBytesTransferred   = C.CString("bytes-transferred")
+	progressKeyStartTime          = C.CString("start-time")
+	progressKeyTotalDeltaParts    = C.CString("total-delta-parts")
+	progressKeyFetchedDeltaParts  = C.CString("fetched-delta-parts")
+	progressKeyScannedMetadata    = C.CString("scanned-metadata")
+	progressKeyMetadataFetched    = C.CString("metadata-fetched")
+)
+
+// NewAsyncProgress creates an OstreeAsyncProgress that invokes cb with a
+// ProgressStatus every time the pull machinery updates it. Pass the
+// returned AsyncProgress to PullWithOptions to observe a transfer. Call
+// Finish when the transfer is done to release the handler registered
+// here.
+func NewAsyncProgress(cb func(status ProgressStatus)) *AsyncProgress {
+	cprogress := C.ostree_async_progress_new()
+
+	progressHandlesMu.Lock()
+	progressHandleSeq++
+	handle := progressHandleSeq
+	progressHandles[handle] = cb
+	progressObjects[unsafe.Pointer(cprogress)] = handle
+	progressHandlesMu.Unlock()
+
+	C._ostree_async_progress_connect_changed(cprogress, C.guintptr(handle))
+
+	return &AsyncProgress{glib.ToGObject(unsafe.Pointer(cprogress))}
+}
+
+// Finish tells the underlying OstreeAsyncProgress that no further updates
+// will be delivered, waking anything blocked on it, and unregisters its
+// change handler so the callback closure can be garbage collected.
+func (a *AsyncProgress) Finish() {
+	cprogress := a.native()
+	C.ostree_async_progress_finish(cprogress)
+
+	progressHandlesMu.Lock()
+	if handle, ok := progressObjects[unsafe.Pointer(cprogress)]; ok {
+		delete(progressObjects, unsafe.Pointer(cprogress))
+		delete(progressHandles, handle)
+	}
+	progressHandlesMu.Unlock()
+}
+
+//export goAsyncProgressChanged
+func goAsyncProgressChanged(progress *C.OstreeAsyncProgress, handle C.guintptr) {
+	progressHandlesMu.Lock()
+	cb, ok := progressHandles[handle]
+	progressHandlesMu.Unlock()
+	if !ok {
+		return
+	}
+
+	status := ProgressStatus{
+		Status:             progressStatusString(progress),
+		OutstandingFetches: uint(C.ostree_async_progress_get_uint(progress, progressKeyOutstandingFetches)),
+		OutstandingWrites:  uint(C.ostree_async_progress_get_uint(progress, progressKeyOutstandingWrites)),
+		Fetched:            uint(C.ostree_async_progress_get_uint(progress, progressKeyFetched)),
+		Requested:          uint(C.ostree_async_progress_get_uint(progress, progressKeyRequested)),
+		BytesTransferred:   uint64(C.ostree_async_progress_get_uint64(progress, progressKeyBytesTransferred)),
+		StartTime:          uint64(C.ostree_async_progress_get_uint64(progress, progressKeyStartTime)),
+		TotalDeltaParts:    uint(C.ostree_async_progress_get_uint(progress, progressKeyTotalDeltaParts)),
+		FetchedDeltaParts:  uint(C.ostree_async_progress_get_uint(progress, progressKeyFetchedDeltaParts)),
+		ScannedMetadata:    uint(C.ostree_async_progress_get_uint(progress, progressKeyScannedMetadata)),
+		MetadataFetched:    uint(C.ostree_async_progress_get_uint(progress, progressKeyMetadataFetched)),
+	}
+
+	cb(status)
+}
+
+func progressStatusString(progress *C.OstreeAsyncProgress) string {
+	cstr := C.ostree_async_progress_get_status(progress)
+	if cstr == nil {
+		return ""
+	}
+	defer C.free(unsafe.Pointer(cstr))
+	return C.GoString(cstr)
+}
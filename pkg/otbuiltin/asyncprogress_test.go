@@ -0,0 +1,62 @@
+package otbuiltin
+
+// #cgo pkg-config: ostree-1
+// #include <glib.h>
+import "C"
+
+import "testing"
+
+func TestProgressStatusPercentComplete(t *testing.T) {
+	cases := []struct {
+		status ProgressStatus
+		want   int
+	}{
+		{ProgressStatus{Requested: 0}, 0},
+		{ProgressStatus{Fetched: 5, Requested: 10}, 50},
+		{ProgressStatus{Fetched: 20, Requested: 10}, 100},
+	}
+	for _, c := range cases {
+		if got := c.status.PercentComplete(); got != c.want {
+			t.Errorf("PercentComplete(%+v) = %d, want %d", c.status, got, c.want)
+		}
+	}
+}
+
+func TestProgressStatusBytesSecUsesMonotonicClock(t *testing.T) {
+	if got := (ProgressStatus{}).BytesSec(); got != 0 {
+		t.Fatalf("BytesSec() with zero StartTime = %d, want 0", got)
+	}
+
+	// start-time is stamped from g_get_monotonic_time, an arbitrary
+	// (non-wall-clock) reference point that can be far larger than
+	// time.Now().UnixMicro(); comparing against the wrong clock used to
+	// underflow this into a huge number instead of a sane rate.
+	start := uint64(C.g_get_monotonic_time()) - 1_000_000
+	status := ProgressStatus{BytesTransferred: 2_000_000, StartTime: start}
+	if got := status.BytesSec(); got == 0 {
+		t.Fatal("BytesSec() = 0 for a transfer one second in, want a nonzero rate")
+	}
+}
+
+func TestNewAsyncProgressFinishUnregistersHandle(t *testing.T) {
+	before := len(progressHandles)
+
+	called := make(chan struct{}, 1)
+	p := NewAsyncProgress(func(ProgressStatus) { called <- struct{}{} })
+
+	progressHandlesMu.Lock()
+	duringCount := len(progressHandles)
+	progressHandlesMu.Unlock()
+	if duringCount != before+1 {
+		t.Fatalf("progressHandles len = %d, want %d after registering", duringCount, before+1)
+	}
+
+	p.Finish()
+
+	progressHandlesMu.Lock()
+	afterCount := len(progressHandles)
+	progressHandlesMu.Unlock()
+	if afterCount != before {
+		t.Fatalf("progressHandles len = %d, want %d after Finish", afterCount, before)
+	}
+}
@@ -92,84 +92,6 @@ func OpenRepo(path string) (*Repo, error) {
 	return repo, nil
 }
 
-type PullOptions struct {
-	OverrideRemoteName string
-	Refs               []string
-}
-
-func (repo *Repo) PullWithOptions(remoteName string, options PullOptions, progress *AsyncProgress, cancellable *glib.GCancellable) error {
-	var cerr *C.GError = nil
-
-	cremoteName := C.CString(remoteName)
-	defer C.free(unsafe.Pointer(cremoteName))
-
-	builder := C.g_variant_builder_new(C._g_variant_type(C.CString("a{sv}")))
-	if options.OverrideRemoteName != "" {
-		cstr := C.CString(options.OverrideRemoteName)
-		v := C.g_variant_new_take_string((*C.gchar)(cstr))
-		k := C.CString("override-remote-name")
-		defer C.free(unsafe.Pointer(k))
-		C._g_variant_builder_add_twoargs(builder, C.CString("{sv}"), k, v)
-	}
-
-	if len(options.Refs) != 0 {
-		crefs := make([]*C.gchar, len(options.Refs))
-		for i, s := range options.Refs {
-			crefs[i] = (*C.gchar)(C.CString(s))
-		}
-
-		v := C.g_variant_new_strv((**C.gchar)(&crefs[0]), (C.gssize)(len(crefs)))
-
-		for i, s := range crefs {
-			crefs[i] = nil
-			C.free(unsafe.Pointer(s))
-		}
-
-		k := C.CString("refs")
-		defer C.free(unsafe.Pointer(k))
-
-		C._g_variant_builder_add_twoargs(builder, C.CString("{sv}"), k, v)
-	}
-
-	coptions := C.g_variant_builder_end(builder)
-
-	r := C.ostree_repo_pull_with_options(repo.native(), cremoteName, coptions, progress.native(), cCancellable(cancellable), &cerr)
-
-	if !gobool(r) {
-		return generateError(cerr)
-	}
-
-	return nil
-}
-
-// enableTombstoneCommits enables support for tombstone commits.
-//
-// This allows to distinguish between intentional deletions and accidental removals
-// of commits.
-func (r *Repo) enableTombstoneCommits() error {
-	if !r.isInitialized() {
-		return errors.New("repo not initialized")
-	}
-
-	config := C.ostree_repo_get_config(r.native())
-	groupC := C.CString("core")
-	defer C.free(unsafe.Pointer(groupC))
-	keyC := C.CString("tombstone-commits")
-	defer C.free(unsafe.Pointer(keyC))
-	valueC := C.g_key_file_get_boolean(config, (*C.gchar)(groupC), (*C.gchar)(keyC), nil)
-	tombstoneCommits := glib.GoBool(glib.GBoolean(valueC))
-
-	// tombstoneCommits is false only if it really is false or if it is set to FALSE in the config file
-	if !tombstoneCommits {
-		var cerr *C.GError
-		C.g_key_file_set_boolean(config, (*C.gchar)(groupC), (*C.gchar)(keyC), C.TRUE)
-		if !glib.GoBool(glib.GBoolean(C.ostree_repo_write_config(r.native(), config, &cerr))) {
-			return generateError(cerr)
-		}
-	}
-	return nil
-}
-
 // generateError wraps a GLib error into a Go one.
 func generateError(err *C.GError) error {
 	if err == nil {
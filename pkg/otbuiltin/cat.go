@@ -0,0 +1,66 @@
+package otbuiltin
+
+import (
+	"errors"
+	"io"
+	"unsafe"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "builtin.go.h"
+import "C"
+
+// Cat writes the contents of path within commit to w, the Go equivalent
+// of `ostree cat`.
+func (repo *Repo) Cat(commit, path string, w io.Writer) error {
+	ccommit := C.CString(commit)
+	defer C.free(unsafe.Pointer(ccommit))
+
+	var root, file *C.GFile
+	var cerr *C.GError
+	r := C.ostree_repo_read_commit(repo.native(), ccommit, &root, nil, nil, &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	defer C.g_object_unref(C.gpointer(root))
+
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	file = C.g_file_resolve_relative_path(root, cpath)
+	defer C.g_object_unref(C.gpointer(file))
+
+	var info *C.GFileInfo
+	info = C.g_file_query_info(file, C.G_FILE_ATTRIBUTE_STANDARD_TYPE, C.G_FILE_QUERY_INFO_NOFOLLOW_SYMLINKS, nil, &cerr)
+	if info == nil {
+		return generateError(cerr)
+	}
+	defer C.g_object_unref(C.gpointer(info))
+
+	if C.g_file_info_get_file_type(info) != C.G_FILE_TYPE_REGULAR {
+		return errors.New("otbuiltin: not a regular file: " + path)
+	}
+
+	stream := C.g_file_read(file, nil, &cerr)
+	if stream == nil {
+		return generateError(cerr)
+	}
+	defer C.g_object_unref(C.gpointer(stream))
+
+	buf := make([]byte, 64*1024)
+	for {
+		n := C.g_input_stream_read((*C.GInputStream)(unsafe.Pointer(stream)), unsafe.Pointer(&buf[0]), C.gsize(len(buf)), nil, &cerr)
+		if n < 0 {
+			return generateError(cerr)
+		}
+		if n == 0 {
+			break
+		}
+		if _, werr := w.Write(buf[:n]); werr != nil {
+			return werr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package otbuiltin
+
+import (
+	"unsafe"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "builtin.go.h"
+import "C"
+
+// CheckoutMode selects how file ownership/permissions are materialized on
+// disk, mirroring OstreeRepoCheckoutMode.
+type CheckoutMode int
+
+const (
+	// CheckoutModeNone preserves uid/gid/xattrs as recorded in the repo;
+	// it requires running as root.
+	CheckoutModeNone CheckoutMode = iota
+	// CheckoutModeUser checks out files owned by the current user,
+	// dropping uid/gid/xattrs that user-mode repos can't represent.
+	CheckoutModeUser
+)
+
+// CheckoutOptions mirrors the options `ostree checkout` accepts.
+type CheckoutOptions struct {
+	Mode      CheckoutMode
+	Overwrite bool
+	// Subpath checks out only this path within the commit, rather than
+	// the whole tree.
+	Subpath string
+	// RequireHardlinks fails instead of silently copying when the repo
+	// can't hardlink an object into place.
+	RequireHardlinks bool
+	// ProcessWhiteouts translates overlayfs-style whiteout files into
+	// actual deletions of the destination path, for container layer
+	// checkouts.
+	ProcessWhiteouts bool
+}
+
+// Checkout materializes commit (or opts.Subpath within it) onto disk at
+// destination, the Go equivalent of `ostree checkout`.
+func (repo *Repo) Checkout(commit, destination string, opts CheckoutOptions) error {
+	var copts C.OstreeRepoCheckoutAtOptions
+	if opts.Mode == CheckoutModeUser {
+		copts.mode = C.OSTREE_REPO_CHECKOUT_MODE_USER
+	} else {
+		copts.mode = C.OSTREE_REPO_CHECKOUT_MODE_NONE
+	}
+	if opts.Overwrite {
+		copts.overwrite_mode = C.OSTREE_REPO_CHECKOUT_OVERWRITE_UNION_FILES
+	} else {
+		copts.overwrite_mode = C.OSTREE_REPO_CHECKOUT_OVERWRITE_NONE
+	}
+	if opts.RequireHardlinks {
+		copts.no_copy_fallback = C.TRUE
+	}
+	if opts.ProcessWhiteouts {
+		copts.process_whiteouts = C.TRUE
+	}
+
+	if opts.Subpath != "" {
+		csubpath := C.CString(opts.Subpath)
+		defer C.free(unsafe.Pointer(csubpath))
+		copts.subpath = csubpath
+	}
+
+	ccommit := C.CString(commit)
+	defer C.free(unsafe.Pointer(ccommit))
+
+	cdest := C.CString(destination)
+	defer C.free(unsafe.Pointer(cdest))
+
+	var cerr *C.GError
+	r := C.ostree_repo_checkout_at(repo.native(), &copts, C.AT_FDCWD, cdest, ccommit, nil, &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
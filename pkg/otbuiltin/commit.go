@@ -0,0 +1,262 @@
+package otbuiltin
+
+import (
+	"time"
+	"unsafe"
+
+	glib "github.com/ostreedev/ostree-go/pkg/glibobject"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "builtin.go.h"
+import "C"
+
+// CommitOptions mirrors the options `ostree commit` accepts.
+type CommitOptions struct {
+	// Parent is the checksum of the commit this one is based on, or ""
+	// to start a new, parentless history.
+	Parent string
+	// Body is the long-form commit message; Subject passed to Commit is
+	// the one-line summary.
+	Body string
+	// Timestamp overrides the commit's recorded time; the zero value
+	// uses the current time.
+	Timestamp time.Time
+	// Tree is the filesystem path, tarball, or existing ref to commit.
+	// TreeIsRef and TreeIsTar select how it is interpreted; the default
+	// is a plain directory.
+	Tree                string
+	TreeIsRef           bool
+	TreeIsTar           bool
+	AddMetadataString   map[string]string
+	// AddDetachedMetadata is written alongside the commit with
+	// ostree_repo_write_commit_detached_metadata instead of being part
+	// of the commit object itself, so it isn't covered by the commit's
+	// checksum.
+	AddDetachedMetadata map[string]string
+	// DisableFsync skips fsync for the writes this commit makes
+	// (ostree_repo_set_disable_fsync), trading durability for speed on
+	// bulk or throwaway commits. The zero value keeps fsync enabled,
+	// matching plain `ostree commit`.
+	DisableFsync bool
+	// GPGSign lists the key IDs to sign the commit with, using the
+	// default GPG homedir for each.
+	GPGSign []string
+	// SelinuxPolicy labels written content according to the policy
+	// rooted at this path, the equivalent of `ostree commit
+	// --selinux-policy`.
+	SelinuxPolicy string
+	// LinkCheckoutSpeedup caches device/inode pairs of existing checkouts
+	// so matching content can be hardlinked into the repo instead of
+	// copied, the equivalent of `ostree commit --link-checkout-speedup`.
+	LinkCheckoutSpeedup bool
+	// NoXattrs omits extended attributes from committed content.
+	NoXattrs bool
+}
+
+// commitModifier builds the OstreeRepoCommitModifier opts.NoXattrs,
+// opts.SelinuxPolicy and opts.LinkCheckoutSpeedup call for, or nil if none
+// of them are set. The caller owns the returned modifier and must unref
+// it.
+func commitModifier(opts CommitOptions) (*C.OstreeRepoCommitModifier, error) {
+	if !opts.NoXattrs && opts.SelinuxPolicy == "" && !opts.LinkCheckoutSpeedup {
+		return nil, nil
+	}
+
+	flags := C.OstreeRepoCommitModifierFlags(C.OSTREE_REPO_COMMIT_MODIFIER_FLAGS_NONE)
+	if opts.NoXattrs {
+		flags |= C.OSTREE_REPO_COMMIT_MODIFIER_FLAGS_SKIP_XATTRS
+	}
+	modifier := C.ostree_repo_commit_modifier_new(flags, nil, nil, nil)
+
+	if opts.SelinuxPolicy != "" {
+		cpath := C.CString(opts.SelinuxPolicy)
+		defer C.free(unsafe.Pointer(cpath))
+		policyFile := C.g_file_new_for_path(cpath)
+		defer C.g_object_unref(C.gpointer(policyFile))
+
+		var cerr *C.GError
+		sepolicy := C.ostree_sepolicy_new(policyFile, nil, &cerr)
+		if sepolicy == nil {
+			C.g_object_unref(C.gpointer(modifier))
+			return nil, generateError(cerr)
+		}
+		defer C.g_object_unref(C.gpointer(sepolicy))
+		C.ostree_repo_commit_modifier_set_sepolicy(modifier, sepolicy)
+	}
+
+	if opts.LinkCheckoutSpeedup {
+		cache := C.ostree_repo_devino_cache_new()
+		defer C.ostree_repo_devino_cache_unref(cache)
+		C.ostree_repo_commit_modifier_set_devino_cache(modifier, cache)
+	}
+
+	return modifier, nil
+}
+
+// Commit writes a new commit on branch from opts.Tree, returning its
+// checksum. It is the Go equivalent of `ostree commit`, wrapping the
+// mtree and commit writes in a transaction so a failure partway through
+// leaves the repo untouched rather than half-written.
+func (repo *Repo) Commit(branch, subject string, opts CommitOptions) (checksum string, err error) {
+	// ostree_repo_set_disable_fsync is repo-global, persistent state, not
+	// scoped to a single call; set it for the duration of this commit and
+	// restore the enabled (default) state before returning, since
+	// libostree exposes no getter to save and restore whatever the prior
+	// value actually was.
+	C.ostree_repo_set_disable_fsync(repo.native(), C.gboolean(glib.GBool(opts.DisableFsync)))
+	defer C.ostree_repo_set_disable_fsync(repo.native(), C.gboolean(glib.GBool(false)))
+
+	var cerr *C.GError
+	if !gobool(C.ostree_repo_prepare_transaction(repo.native(), nil, nil, &cerr)) {
+		return "", generateError(cerr)
+	}
+	defer func() {
+		if err != nil {
+			C.ostree_repo_abort_transaction(repo.native(), nil, nil)
+		}
+	}()
+
+	modifier, err := commitModifier(opts)
+	if err != nil {
+		return "", err
+	}
+	if modifier != nil {
+		defer C.g_object_unref(C.gpointer(modifier))
+	}
+
+	mtree, err := repo.writeMtreeFromOptions(opts, modifier)
+	if err != nil {
+		return "", err
+	}
+
+	var root *C.GFile
+	if !gobool(C.ostree_repo_write_mtree(repo.native(), mtree, (**C.OstreeRepoFile)(unsafe.Pointer(&root)), nil, &cerr)) {
+		return "", generateError(cerr)
+	}
+
+	cparent := (*C.char)(nil)
+	if opts.Parent != "" {
+		cparent = C.CString(opts.Parent)
+		defer C.free(unsafe.Pointer(cparent))
+	}
+
+	csubject := C.CString(subject)
+	defer C.free(unsafe.Pointer(csubject))
+
+	cbody := (*C.char)(nil)
+	if opts.Body != "" {
+		cbody = C.CString(opts.Body)
+		defer C.free(unsafe.Pointer(cbody))
+	}
+
+	metadata := stringMapToVariant(opts.AddMetadataString)
+
+	var ctimestamp C.guint64
+	if !opts.Timestamp.IsZero() {
+		ctimestamp = C.guint64(opts.Timestamp.Unix())
+	} else {
+		ctimestamp = C.guint64(time.Now().Unix())
+	}
+
+	var newChecksum *C.char
+	if !gobool(C.ostree_repo_write_commit_with_time(repo.native(), cparent, csubject, cbody, metadata,
+		(*C.OstreeRepoFile)(unsafe.Pointer(root)), ctimestamp, &newChecksum, nil, &cerr)) {
+		return "", generateError(cerr)
+	}
+	defer C.free(unsafe.Pointer(newChecksum))
+
+	checksum = C.GoString(newChecksum)
+	cchecksum := C.CString(checksum)
+	defer C.free(unsafe.Pointer(cchecksum))
+
+	if len(opts.AddDetachedMetadata) > 0 {
+		detached := stringMapToVariant(opts.AddDetachedMetadata)
+		if !gobool(C.ostree_repo_write_commit_detached_metadata(repo.native(), cchecksum, detached, nil, &cerr)) {
+			return "", generateError(cerr)
+		}
+	}
+
+	for _, keyID := range opts.GPGSign {
+		ckeyID := C.CString(keyID)
+		r := C.ostree_repo_sign_commit(repo.native(), cchecksum, ckeyID, nil, nil, &cerr)
+		C.free(unsafe.Pointer(ckeyID))
+		if !gobool(r) {
+			return "", generateError(cerr)
+		}
+	}
+
+	cbranch := C.CString(branch)
+	defer C.free(unsafe.Pointer(cbranch))
+	C.ostree_repo_transaction_set_ref(repo.native(), nil, cbranch, cchecksum)
+
+	if !gobool(C.ostree_repo_commit_transaction(repo.native(), nil, nil, &cerr)) {
+		return "", generateError(cerr)
+	}
+
+	return checksum, nil
+}
+
+// stringMapToVariant builds the a{sv} GVariant libostree's commit and
+// detached-metadata calls expect from a plain string-keyed, string-valued
+// map.
+func stringMapToVariant(values map[string]string) *C.GVariant {
+	builder := C.g_variant_builder_new(C._g_variant_type(C.CString("a{sv}")))
+	for k, v := range values {
+		ck := C.CString(k)
+		cv := C.g_variant_new_take_string((*C.gchar)(C.CString(v)))
+		C._g_variant_builder_add_twoargs(builder, C.CString("{sv}"), ck, cv)
+		C.free(unsafe.Pointer(ck))
+	}
+	return C.g_variant_builder_end(builder)
+}
+
+// writeMtreeFromOptions builds an OstreeMutableTree from opts.Tree,
+// handling the directory/tarball/ref source forms Commit accepts and
+// applying modifier (which may be nil) to whichever one is used.
+func (repo *Repo) writeMtreeFromOptions(opts CommitOptions, modifier *C.OstreeRepoCommitModifier) (*C.OstreeMutableTree, error) {
+	mtree := C.ostree_mutable_tree_new()
+
+	cpath := C.CString(opts.Tree)
+	defer C.free(unsafe.Pointer(cpath))
+
+	if opts.TreeIsRef {
+		var cerr *C.GError
+		var root *C.GFile
+		var commit *C.char
+		r := C.ostree_repo_read_commit(repo.native(), cpath, &root, &commit, nil, &cerr)
+		if !gobool(r) {
+			return nil, generateError(cerr)
+		}
+		defer C.free(unsafe.Pointer(commit))
+
+		var cerr2 *C.GError
+		r2 := C.ostree_repo_write_directory_to_mtree(repo.native(), root, mtree, modifier, nil, &cerr2)
+		if !gobool(r2) {
+			return nil, generateError(cerr2)
+		}
+		return mtree, nil
+	}
+
+	file := C.g_file_new_for_path(cpath)
+	defer C.g_object_unref(C.gpointer(file))
+
+	var cerr *C.GError
+	if opts.TreeIsTar {
+		r := C.ostree_repo_write_archive_to_mtree(repo.native(), file, mtree, modifier, C.TRUE, nil, &cerr)
+		if !gobool(r) {
+			return nil, generateError(cerr)
+		}
+		return mtree, nil
+	}
+
+	r := C.ostree_repo_write_directory_to_mtree(repo.native(), file, mtree, modifier, nil, &cerr)
+	if !gobool(r) {
+		return nil, generateError(cerr)
+	}
+
+	return mtree, nil
+}
@@ -0,0 +1,72 @@
+package otbuiltin
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCommitCheckoutCatRoundTrip(t *testing.T) {
+	repo, dir := newTestRepo(t)
+
+	srcDir := filepath.Join(dir, "src")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "hello.txt"), []byte("hello world"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	checksum, err := repo.Commit("test-branch", "initial commit", CommitOptions{Tree: srcDir})
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if checksum == "" {
+		t.Fatal("expected a non-empty checksum")
+	}
+
+	checkoutDir := filepath.Join(dir, "checkout")
+	if err := repo.Checkout(checksum, checkoutDir, CheckoutOptions{Mode: CheckoutModeUser}); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(checkoutDir, "hello.txt"))
+	if err != nil {
+		t.Fatalf("reading checked out file: %v", err)
+	}
+	if string(got) != "hello world" {
+		t.Fatalf("checked out content = %q, want %q", got, "hello world")
+	}
+
+	var buf bytes.Buffer
+	if err := repo.Cat(checksum, "/hello.txt", &buf); err != nil {
+		t.Fatalf("Cat: %v", err)
+	}
+	if buf.String() != "hello world" {
+		t.Fatalf("Cat content = %q, want %q", buf.String(), "hello world")
+	}
+}
+
+func TestCommitRejectsBadParent(t *testing.T) {
+	repo, dir := newTestRepo(t)
+
+	srcDir := filepath.Join(dir, "src")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := repo.Commit("test-branch", "bad parent", CommitOptions{
+		Tree:   srcDir,
+		Parent: "0000000000000000000000000000000000000000000000000000000000000000",
+	})
+	if err == nil {
+		t.Fatal("expected an error committing against a nonexistent parent")
+	}
+
+	// A failed commit must leave no half-written ref behind; the
+	// transaction wrapping Commit now uses should have aborted cleanly.
+	if _, err := repo.ResolveRev("test-branch", false); err == nil {
+		t.Fatal("expected test-branch to remain unresolved after the aborted commit")
+	}
+}
@@ -0,0 +1,102 @@
+package otbuiltin
+
+import (
+	"errors"
+	"unsafe"
+
+	glib "github.com/ostreedev/ostree-go/pkg/glibobject"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "builtin.go.h"
+import "C"
+
+// KeyFile is a thin wrapper around a GKeyFile, the ini-style format
+// repo.conf (and remote config) is stored in.
+type KeyFile struct {
+	ptr  *C.GKeyFile
+	repo *Repo
+}
+
+// Config returns a KeyFile wrapping repo's current in-memory config.
+// Call Save to persist changes back to repo.conf.
+func (repo *Repo) Config() *KeyFile {
+	return &KeyFile{ptr: C.ostree_repo_get_config(repo.native()), repo: repo}
+}
+
+// Get returns the string value of key in group, or "" if it is unset.
+func (k *KeyFile) Get(group, key string) string {
+	cgroup := C.CString(group)
+	defer C.free(unsafe.Pointer(cgroup))
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+
+	cvalue := C.g_key_file_get_string(k.ptr, cgroup, ckey, nil)
+	if cvalue == nil {
+		return ""
+	}
+	defer C.free(unsafe.Pointer(cvalue))
+	return C.GoString(cvalue)
+}
+
+// GetBool returns the boolean value of key in group.
+func (k *KeyFile) GetBool(group, key string) bool {
+	cgroup := C.CString(group)
+	defer C.free(unsafe.Pointer(cgroup))
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+
+	return gobool(C.g_key_file_get_boolean(k.ptr, cgroup, ckey, nil))
+}
+
+// Set stores a string value for key in group.
+func (k *KeyFile) Set(group, key, value string) {
+	cgroup := C.CString(group)
+	defer C.free(unsafe.Pointer(cgroup))
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+	cvalue := C.CString(value)
+	defer C.free(unsafe.Pointer(cvalue))
+
+	C.g_key_file_set_string(k.ptr, cgroup, ckey, cvalue)
+}
+
+// SetBool stores a boolean value for key in group.
+func (k *KeyFile) SetBool(group, key string, value bool) {
+	cgroup := C.CString(group)
+	defer C.free(unsafe.Pointer(cgroup))
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+
+	C.g_key_file_set_boolean(k.ptr, cgroup, ckey, C.gboolean(glib.GBool(value)))
+}
+
+// Save writes the keyfile back to the repo's repo.conf.
+func (k *KeyFile) Save() error {
+	var cerr *C.GError
+	r := C.ostree_repo_write_config(k.repo.native(), k.ptr, &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
+
+// enableTombstoneCommits enables support for tombstone commits.
+//
+// This allows to distinguish between intentional deletions and accidental removals
+// of commits.
+func (r *Repo) enableTombstoneCommits() error {
+	if !r.isInitialized() {
+		return errors.New("repo not initialized")
+	}
+
+	config := r.Config()
+	if !config.GetBool("core", "tombstone-commits") {
+		config.SetBool("core", "tombstone-commits", true)
+		return config.Save()
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package otbuiltin
+
+import (
+	"unsafe"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "builtin.go.h"
+import "C"
+
+// Diff compares the commits resolved by revA and revB, the Go equivalent
+// of `ostree diff`. Paths are relative to the tree root.
+func (repo *Repo) Diff(revA, revB string) (modified, removed, added []string, err error) {
+	rootA, err := repo.readCommitRoot(revA)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer C.g_object_unref(C.gpointer(rootA))
+
+	rootB, err := repo.readCommitRoot(revB)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer C.g_object_unref(C.gpointer(rootB))
+
+	cmodified := C.g_ptr_array_new()
+	defer C.g_ptr_array_unref(cmodified)
+	cremoved := C.g_ptr_array_new()
+	defer C.g_ptr_array_unref(cremoved)
+	cadded := C.g_ptr_array_new()
+	defer C.g_ptr_array_unref(cadded)
+
+	var cerr *C.GError
+	r := C.ostree_diff_dirs(0, rootA, rootB, cmodified, cremoved, cadded, nil, &cerr)
+	if !gobool(r) {
+		return nil, nil, nil, generateError(cerr)
+	}
+
+	modified = diffItemPathsRepo(cmodified)
+	removed = gfilePathsRepo(cremoved)
+	added = gfilePathsRepo(cadded)
+	return modified, removed, added, nil
+}
+
+func (repo *Repo) readCommitRoot(rev string) (*C.GFile, error) {
+	crev := C.CString(rev)
+	defer C.free(unsafe.Pointer(crev))
+
+	var root *C.GFile
+	var cerr *C.GError
+	r := C.ostree_repo_read_commit(repo.native(), crev, &root, nil, nil, &cerr)
+	if !gobool(r) {
+		return nil, generateError(cerr)
+	}
+	return root, nil
+}
+
+func diffItemPathsRepo(arr *C.GPtrArray) []string {
+	n := int(arr.len)
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		item := (*C.OstreeDiffItem)(C.g_ptr_array_index(arr, C.guint(i)))
+		if item == nil || item.src == nil {
+			continue
+		}
+		cpath := C.g_file_get_path(item.src)
+		if cpath != nil {
+			out = append(out, C.GoString(cpath))
+			C.free(unsafe.Pointer(cpath))
+		}
+	}
+	return out
+}
+
+func gfilePathsRepo(arr *C.GPtrArray) []string {
+	n := int(arr.len)
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		f := (*C.GFile)(C.g_ptr_array_index(arr, C.guint(i)))
+		cpath := C.g_file_get_path(f)
+		if cpath != nil {
+			out = append(out, C.GoString(cpath))
+			C.free(unsafe.Pointer(cpath))
+		}
+	}
+	return out
+}
@@ -0,0 +1,33 @@
+package otbuiltin
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "builtin.go.h"
+import "C"
+
+// FsckOptions mirrors the options `ostree fsck` accepts.
+type FsckOptions struct {
+	// Quiet suppresses the per-object progress output fsck normally
+	// prints while it walks the repo (OSTREE_REPO_FSCK_QUIET). It does
+	// not change which objects get checked -- libostree has no cache of
+	// previously verified objects to skip.
+	Quiet bool
+}
+
+// Fsck walks every object in repo, validating that its checksum matches
+// its content, the Go equivalent of `ostree fsck`.
+func (repo *Repo) Fsck(opts FsckOptions) error {
+	flags := C.OSTREE_REPO_FSCK_NONE
+	if opts.Quiet {
+		flags |= C.OSTREE_REPO_FSCK_QUIET
+	}
+
+	var cerr *C.GError
+	r := C.ostree_repo_fsck(repo.native(), C.OstreeRepoFsckFlags(flags), nil, &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package otbuiltin
+
+import (
+	"time"
+	"unsafe"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "builtin.go.h"
+import "C"
+
+// CommitInfo summarizes one entry in a commit's ancestry, as returned by
+// Log.
+type CommitInfo struct {
+	Checksum string
+	Subject  string
+	Body     string
+	Time     time.Time
+}
+
+// Log walks ref's ancestry, most recent first, the Go equivalent of
+// `ostree log`. limit caps how many commits are returned; 0 means no
+// limit.
+func (repo *Repo) Log(ref string, limit int) ([]CommitInfo, error) {
+	cref := C.CString(ref)
+	defer C.free(unsafe.Pointer(cref))
+
+	var checksum *C.char
+	var cerr *C.GError
+	r := C.ostree_repo_resolve_rev(repo.native(), cref, C.FALSE, &checksum, &cerr)
+	if !gobool(r) {
+		return nil, generateError(cerr)
+	}
+	defer C.free(unsafe.Pointer(checksum))
+
+	var commits []CommitInfo
+	current := C.GoString(checksum)
+	for current != "" {
+		if limit > 0 && len(commits) >= limit {
+			break
+		}
+
+		ccurrent := C.CString(current)
+		var variant *C.GVariant
+		r := C.ostree_repo_load_variant(repo.native(), C.OSTREE_OBJECT_TYPE_COMMIT, ccurrent, &variant, &cerr)
+		C.free(unsafe.Pointer(ccurrent))
+		if !gobool(r) {
+			return commits, generateError(cerr)
+		}
+
+		info := CommitInfo{
+			Checksum: current,
+			Subject:  commitVariantString(variant, 3),
+			Body:     commitVariantString(variant, 4),
+			Time:     time.Unix(int64(C.ostree_commit_get_timestamp(variant)), 0),
+		}
+		commits = append(commits, info)
+
+		parentC := C.ostree_commit_get_parent(variant)
+		C.g_variant_unref(variant)
+		if parentC == nil {
+			break
+		}
+		current = C.GoString(parentC)
+		C.free(unsafe.Pointer(parentC))
+	}
+
+	return commits, nil
+}
+
+// commitVariantString extracts the string at tuple index idx from a
+// commit variant ((a{sv} metadata, ay parent, a(say) related, s subject,
+// s body, tt timestamp, ay root-tree-contents, ay root-tree-meta)).
+func commitVariantString(variant *C.GVariant, idx C.gsize) string {
+	child := C.g_variant_get_child_value(variant, idx)
+	defer C.g_variant_unref(child)
+	cstr := C.g_variant_get_string(child, nil)
+	if cstr == nil {
+		return ""
+	}
+	return C.GoString(cstr)
+}
@@ -0,0 +1,128 @@
+package otbuiltin
+
+import (
+	"unsafe"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "builtin.go.h"
+import "C"
+
+// DirEntry is one file or directory listed by Ls.
+type DirEntry struct {
+	Path string
+	Type string // "file", "directory", "symlink"
+	Size int64
+	Mode uint32
+}
+
+// lsAttributes/lsModeAttribute are allocated once rather than per lsWalk
+// call, since lsWalk recurses once per directory (and, for the child
+// lookup in its enumeration loop, potentially once per file) in a large
+// tree.
+var (
+	lsAttributes    = C.CString("standard::*,unix::mode")
+	lsModeAttribute = C.CString("unix::mode")
+)
+
+func dirEntryFromInfo(path string, info *C.GFileInfo) DirEntry {
+	entry := DirEntry{
+		Path: path,
+		Size: int64(C.g_file_info_get_size(info)),
+		Mode: uint32(C.g_file_info_get_attribute_uint32(info, lsModeAttribute)),
+	}
+	switch C.g_file_info_get_file_type(info) {
+	case C.G_FILE_TYPE_DIRECTORY:
+		entry.Type = "directory"
+	case C.G_FILE_TYPE_SYMBOLIC_LINK:
+		entry.Type = "symlink"
+	default:
+		entry.Type = "file"
+	}
+	return entry
+}
+
+// Ls lists path within commit, the Go equivalent of `ostree ls`. When
+// recursive is true it descends into directories, returning every entry
+// under path rather than just its immediate children.
+func (repo *Repo) Ls(commit, path string, recursive bool) ([]DirEntry, error) {
+	ccommit := C.CString(commit)
+	defer C.free(unsafe.Pointer(ccommit))
+
+	var root *C.GFile
+	var cerr *C.GError
+	r := C.ostree_repo_read_commit(repo.native(), ccommit, &root, nil, nil, &cerr)
+	if !gobool(r) {
+		return nil, generateError(cerr)
+	}
+	defer C.g_object_unref(C.gpointer(root))
+
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	target := C.g_file_resolve_relative_path(root, cpath)
+	defer C.g_object_unref(C.gpointer(target))
+
+	var entries []DirEntry
+	if err := lsWalk(target, path, recursive, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func lsWalk(file *C.GFile, path string, recursive bool, entries *[]DirEntry) error {
+	var cerr *C.GError
+	info := C.g_file_query_info(file, lsAttributes, C.G_FILE_QUERY_INFO_NOFOLLOW_SYMLINKS, nil, &cerr)
+	if info == nil {
+		return generateError(cerr)
+	}
+	defer C.g_object_unref(C.gpointer(info))
+
+	entry := dirEntryFromInfo(path, info)
+	*entries = append(*entries, entry)
+
+	if entry.Type != "directory" {
+		return nil
+	}
+
+	enumerator := C.g_file_enumerate_children(file, lsAttributes, C.G_FILE_QUERY_INFO_NOFOLLOW_SYMLINKS, nil, &cerr)
+	if enumerator == nil {
+		return generateError(cerr)
+	}
+	defer C.g_object_unref(C.gpointer(enumerator))
+
+	for {
+		var childErr *C.GError
+		childInfo := C.g_file_enumerator_next_file(enumerator, nil, &childErr)
+		if childInfo == nil {
+			if childErr != nil {
+				return generateError(childErr)
+			}
+			break
+		}
+
+		name := C.GoString(C.g_file_info_get_name(childInfo))
+		childPath := path + "/" + name
+
+		cname := C.CString(name)
+		child := C.g_file_get_child(file, cname)
+		C.free(unsafe.Pointer(cname))
+
+		if recursive {
+			err := lsWalk(child, childPath, true, entries)
+			C.g_object_unref(C.gpointer(childInfo))
+			C.g_object_unref(C.gpointer(child))
+			if err != nil {
+				return err
+			}
+		} else {
+			*entries = append(*entries, dirEntryFromInfo(childPath, childInfo))
+			C.g_object_unref(C.gpointer(childInfo))
+			C.g_object_unref(C.gpointer(child))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package otbuiltin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLsNonRecursiveSetsMode(t *testing.T) {
+	repo, dir := newTestRepo(t)
+
+	srcDir := filepath.Join(dir, "src")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "hello.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	checksum, err := repo.Commit("test-branch", "initial commit", CommitOptions{Tree: srcDir})
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	entries, err := repo.Ls(checksum, "/", false)
+	if err != nil {
+		t.Fatalf("Ls: %v", err)
+	}
+
+	var found bool
+	for _, e := range entries {
+		if e.Path != "/hello.txt" {
+			continue
+		}
+		found = true
+		if e.Mode == 0 {
+			t.Fatalf("non-recursive Ls entry for %q has Mode == 0, want the file's real mode bits", e.Path)
+		}
+	}
+	if !found {
+		t.Fatalf("expected an entry for /hello.txt in %+v", entries)
+	}
+}
@@ -0,0 +1,76 @@
+package otbuiltin
+
+import (
+	"errors"
+	"time"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "builtin.go.h"
+import "C"
+
+// PruneOptions mirrors the options `ostree prune` accepts.
+type PruneOptions struct {
+	// RefsOnly only considers commits reachable from refs, skipping
+	// unreferenced commits kept alive solely by being recent.
+	RefsOnly bool
+	// Depth limits how many parents back from each ref are kept;
+	// -1, and the zero value, mean keep the whole history.
+	Depth int
+	// KeepYoungerThan additionally retains any commit newer than this
+	// time regardless of Depth. libostree's prune has no native
+	// time-based cutoff, so this is not implemented: setting it makes
+	// Prune return an error rather than silently discarding a
+	// safety-relevant retention parameter. Callers that need it should
+	// walk Log themselves and pick a Depth that covers the window they
+	// want.
+	KeepYoungerThan time.Time
+	// Static performs a dry run: compute what would be deleted without
+	// deleting it.
+	Static bool
+}
+
+// PruneStats reports what Prune did or, with opts.Static set, would do.
+type PruneStats struct {
+	TotalObjects     int
+	PrunedObjects    int
+	ObjectsTotalSize uint64
+}
+
+// Prune deletes unreachable objects from repo, the Go equivalent of
+// `ostree prune`.
+func (repo *Repo) Prune(opts PruneOptions) (PruneStats, error) {
+	if !opts.KeepYoungerThan.IsZero() {
+		return PruneStats{}, errors.New("otbuiltin: PruneOptions.KeepYoungerThan is not implemented; libostree's prune has no time-based cutoff, so this would silently discard the requested retention guarantee")
+	}
+
+	flags := C.OSTREE_REPO_PRUNE_FLAGS_NONE
+	if opts.RefsOnly {
+		flags |= C.OSTREE_REPO_PRUNE_FLAGS_REFS_ONLY
+	}
+	if opts.Static {
+		flags |= C.OSTREE_REPO_PRUNE_FLAGS_NO_PRUNE
+	}
+
+	depth := C.gint(-1)
+	if opts.Depth > 0 {
+		depth = C.gint(opts.Depth)
+	}
+
+	var totalObjects, prunedObjects C.gint
+	var objectsTotalSize C.guint64
+	var cerr *C.GError
+	r := C.ostree_repo_prune(repo.native(), C.OstreeRepoPruneFlags(flags), depth, &totalObjects, &prunedObjects, &objectsTotalSize, nil, &cerr)
+	if !gobool(r) {
+		return PruneStats{}, generateError(cerr)
+	}
+
+	return PruneStats{
+		TotalObjects:     int(totalObjects),
+		PrunedObjects:    int(prunedObjects),
+		ObjectsTotalSize: uint64(objectsTotalSize),
+	}, nil
+}
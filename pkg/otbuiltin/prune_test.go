@@ -0,0 +1,14 @@
+package otbuiltin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPruneRejectsKeepYoungerThan(t *testing.T) {
+	repo := &Repo{}
+	_, err := repo.Prune(PruneOptions{KeepYoungerThan: time.Now()})
+	if err == nil {
+		t.Fatal("expected an error when KeepYoungerThan is set, got nil")
+	}
+}
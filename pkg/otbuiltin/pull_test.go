@@ -0,0 +1,60 @@
+package otbuiltin
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "builtin.go.h"
+import "C"
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func lookupBoolOption(t *testing.T, v *C.GVariant, key string) (bool, bool) {
+	t.Helper()
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+	value := C.g_variant_lookup_value(v, ckey, C._g_variant_type(C.CString("b")))
+	if value == nil {
+		return false, false
+	}
+	defer C.g_variant_unref(value)
+	return gobool(C.g_variant_get_boolean(value)), true
+}
+
+func lookupInt32Option(t *testing.T, v *C.GVariant, key string) (int32, bool) {
+	t.Helper()
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+	value := C.g_variant_lookup_value(v, ckey, C._g_variant_type(C.CString("i")))
+	if value == nil {
+		return 0, false
+	}
+	defer C.g_variant_unref(value)
+	return int32(C.g_variant_get_int32(value)), true
+}
+
+func TestBuildPullOptionsNetworkRetriesKey(t *testing.T) {
+	v := buildPullOptions(PullOptions{NetworkRetries: 5})
+	defer C.g_variant_unref(v)
+
+	got, ok := lookupInt32Option(t, v, "n-network-retries")
+	if !ok || got != 5 {
+		t.Fatalf("n-network-retries = %d, %v; want 5, true", got, ok)
+	}
+}
+
+func TestBuildPullOptionsOmitsBogusTimestampCheckKey(t *testing.T) {
+	v := buildPullOptions(PullOptions{Timestamp: 1234, Refs: []string{"main"}})
+	defer C.g_variant_unref(v)
+
+	if _, ok := lookupInt32Option(t, v, "timestamp-check"); ok {
+		t.Fatal("timestamp-check should not be written as an int64/int32 option; it is enforced in Go post-pull")
+	}
+
+	if _, ok := lookupBoolOption(t, v, "timestamp-check"); ok {
+		t.Fatal("timestamp-check should not be set at all; the Timestamp cutoff is enforced in Go post-pull, not via this key")
+	}
+}
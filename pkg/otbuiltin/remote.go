@@ -0,0 +1,278 @@
+package otbuiltin
+
+import (
+	"io"
+	"unsafe"
+
+	glib "github.com/ostreedev/ostree-go/pkg/glibobject"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "builtin.go.h"
+import "C"
+
+// RemoteOptions mirrors the options `ostree remote add` accepts.
+type RemoteOptions struct {
+	GPGVerify        Tristate
+	GPGVerifySummary Tristate
+
+	TLSClientCertPath string
+	TLSClientKeyPath  string
+	TLSCAPath         string
+	TLSPermissive     bool
+
+	// ContentURL, if set, serves static content (summary, objects) from
+	// a different URL than metadata requests use.
+	ContentURL string
+	Proxy      string
+
+	// CustomBackend names an alternate OstreeFetcher backend registered
+	// for this remote, instead of the default libsoup/curl HTTP fetcher.
+	CustomBackend string
+	CollectionID  string
+}
+
+func (opts RemoteOptions) toVariant() *C.GVariant {
+	builder := C.g_variant_builder_new(C._g_variant_type(C.CString("a{sv}")))
+
+	addString := func(key, value string) {
+		if value == "" {
+			return
+		}
+		cstr := C.CString(value)
+		v := C.g_variant_new_take_string((*C.gchar)(cstr))
+		k := C.CString(key)
+		defer C.free(unsafe.Pointer(k))
+		C._g_variant_builder_add_twoargs(builder, C.CString("{sv}"), k, v)
+	}
+
+	addBool := func(key string, value bool) {
+		v := C.g_variant_new_boolean(C.gboolean(glib.GBool(value)))
+		k := C.CString(key)
+		defer C.free(unsafe.Pointer(k))
+		C._g_variant_builder_add_twoargs(builder, C.CString("{sv}"), k, v)
+	}
+
+	if opts.GPGVerify != TristateDefault {
+		addBool("gpg-verify", opts.GPGVerify == TristateTrue)
+	}
+	if opts.GPGVerifySummary != TristateDefault {
+		addBool("gpg-verify-summary", opts.GPGVerifySummary == TristateTrue)
+	}
+
+	addString("tls-client-cert-path", opts.TLSClientCertPath)
+	addString("tls-client-key-path", opts.TLSClientKeyPath)
+	addString("tls-ca-path", opts.TLSCAPath)
+	if opts.TLSPermissive {
+		addBool("tls-permissive", true)
+	}
+	addString("contenturl", opts.ContentURL)
+	addString("proxy", opts.Proxy)
+	addString("custom-backend", opts.CustomBackend)
+	addString("collection-id", opts.CollectionID)
+
+	return C.g_variant_builder_end(builder)
+}
+
+// AddRemote registers a new remote named name pointed at url, the Go
+// equivalent of `ostree remote add`.
+func (repo *Repo) AddRemote(name, url string, opts RemoteOptions) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	curl := C.CString(url)
+	defer C.free(unsafe.Pointer(curl))
+
+	coptions := opts.toVariant()
+
+	var cerr *C.GError
+	r := C.ostree_repo_remote_add(repo.native(), cname, curl, coptions, nil, &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
+
+// DeleteRemote removes a previously configured remote, the Go equivalent
+// of `ostree remote delete`.
+func (repo *Repo) DeleteRemote(name string) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	var cerr *C.GError
+	r := C.ostree_repo_remote_delete(repo.native(), cname, nil, &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
+
+// ListRemotes returns the names of every remote configured in repo, the
+// Go equivalent of `ostree remote list`.
+func (repo *Repo) ListRemotes() ([]string, error) {
+	cremotes := C.ostree_repo_remote_list(repo.native(), nil)
+	if cremotes == nil {
+		return nil, nil
+	}
+	defer C.g_strfreev(cremotes)
+
+	var remotes []string
+	for p := cremotes; *p != nil; p = advanceCharPtr(p) {
+		remotes = append(remotes, C.GoString(*p))
+	}
+	return remotes, nil
+}
+
+func advanceCharPtr(p **C.char) **C.char {
+	return (**C.char)(unsafe.Pointer(uintptr(unsafe.Pointer(p)) + unsafe.Sizeof(*p)))
+}
+
+// RemoteGetURL returns the URL configured for name.
+func (repo *Repo) RemoteGetURL(name string) (string, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	var curl *C.char
+	var cerr *C.GError
+	r := C.ostree_repo_remote_get_url(repo.native(), cname, &curl, &cerr)
+	if !gobool(r) {
+		return "", generateError(cerr)
+	}
+	defer C.free(unsafe.Pointer(curl))
+	return C.GoString(curl), nil
+}
+
+// RemoteListRefs fetches the ref -> checksum map a remote currently
+// advertises, the Go equivalent of `ostree remote refs`.
+func (repo *Repo) RemoteListRefs(name string) (map[string]string, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	var crefs *C.GHashTable
+	var cerr *C.GError
+	r := C.ostree_repo_remote_list_refs(repo.native(), cname, &crefs, nil, &cerr)
+	if !gobool(r) {
+		return nil, generateError(cerr)
+	}
+	defer C.g_hash_table_unref(crefs)
+
+	refs := make(map[string]string)
+	var iter C.GHashTableIter
+	var key, value C.gpointer
+	C.g_hash_table_iter_init(&iter, crefs)
+	for gobool(C.g_hash_table_iter_next(&iter, &key, &value)) {
+		refs[C.GoString((*C.char)(key))] = C.GoString((*C.char)(value))
+	}
+	return refs, nil
+}
+
+// RemoteGPGImport imports GPG keys read from keyData into the keyring
+// used to verify commits from name, the Go equivalent of
+// `ostree remote gpg-import`. It returns the number of keys imported.
+func (repo *Repo) RemoteGPGImport(name string, keyData io.Reader) (int, error) {
+	data, err := io.ReadAll(keyData)
+	if err != nil {
+		return 0, err
+	}
+
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	var cdata *C.char
+	if len(data) > 0 {
+		cdata = (*C.char)(C.CBytes(data))
+		defer C.free(unsafe.Pointer(cdata))
+	}
+	bytes := C.g_bytes_new(C.gconstpointer(cdata), C.gsize(len(data)))
+	defer C.g_bytes_unref(bytes)
+
+	stream := C.g_memory_input_stream_new_from_bytes(bytes)
+	defer C.g_object_unref(C.gpointer(stream))
+
+	var imported C.guint
+	var cerr *C.GError
+	r := C.ostree_repo_remote_gpg_import(repo.native(), cname, (*C.GInputStream)(unsafe.Pointer(stream)), nil, &imported, nil, &cerr)
+	if !gobool(r) {
+		return 0, generateError(cerr)
+	}
+	return int(imported), nil
+}
+
+// GPGSignature is one signature on a commit, as returned by VerifyCommit.
+type GPGSignature struct {
+	Valid       bool
+	KeyID       string
+	Fingerprint string
+}
+
+// GPGVerifyResult summarizes every signature found on a commit.
+type GPGVerifyResult struct {
+	Signatures []GPGSignature
+}
+
+// AllValid reports whether every signature on the commit validated; it
+// is false when the commit has no signatures.
+func (r *GPGVerifyResult) AllValid() bool {
+	if len(r.Signatures) == 0 {
+		return false
+	}
+	for _, s := range r.Signatures {
+		if !s.Valid {
+			return false
+		}
+	}
+	return true
+}
+
+// VerifyCommit checks commit's GPG signature(s) against the keyring
+// configured for remoteName, the Go equivalent of
+// `ostree show --print-sigs`.
+func (repo *Repo) VerifyCommit(commit, remoteName string) (*GPGVerifyResult, error) {
+	ccommit := C.CString(commit)
+	defer C.free(unsafe.Pointer(ccommit))
+	cremote := C.CString(remoteName)
+	defer C.free(unsafe.Pointer(cremote))
+
+	var cerr *C.GError
+	result := C.ostree_repo_verify_commit_for_remote(repo.native(), ccommit, cremote, nil, &cerr)
+	if result == nil {
+		return nil, generateError(cerr)
+	}
+	defer C.g_object_unref(C.gpointer(result))
+
+	n := int(C.ostree_gpg_verify_result_count_all(result))
+	sigs := make([]GPGSignature, 0, n)
+	for i := 0; i < n; i++ {
+		idx := C.guint(i)
+
+		validVariant := C.ostree_gpg_verify_result_get(result, idx, C.OSTREE_GPG_SIGNATURE_ATTR_VALID)
+		valid := validVariant != nil && gobool(C.g_variant_get_boolean(validVariant))
+		if validVariant != nil {
+			C.g_variant_unref(validVariant)
+		}
+
+		sigs = append(sigs, GPGSignature{
+			Valid:       valid,
+			KeyID:       gpgResultString(result, idx, C.OSTREE_GPG_SIGNATURE_ATTR_KEY_ID),
+			Fingerprint: gpgResultString(result, idx, C.OSTREE_GPG_SIGNATURE_ATTR_FINGERPRINT),
+		})
+	}
+
+	return &GPGVerifyResult{Signatures: sigs}, nil
+}
+
+func gpgResultString(result *C.OstreeGpgVerifyResult, idx C.guint, attr C.OstreeGpgSignatureAttr) string {
+	v := C.ostree_gpg_verify_result_get(result, idx, attr)
+	if v == nil {
+		return ""
+	}
+	defer C.g_variant_unref(v)
+
+	cstr := C.g_variant_get_string(v, nil)
+	if cstr == nil {
+		return ""
+	}
+	return C.GoString(cstr)
+}
@@ -0,0 +1,220 @@
+package otbuiltin
+
+import (
+	"io"
+	"os"
+	"unsafe"
+)
+
+// #cgo pkg-config: ostree-1
+// #include <stdlib.h>
+// #include <glib.h>
+// #include <ostree.h>
+// #include "builtin.go.h"
+import "C"
+
+// StaticDeltaOptions mirrors the options `ostree static-delta generate`
+// accepts.
+type StaticDeltaOptions struct {
+	// MinFallbackSize is the minimum object size, in MiB, below which
+	// content is always inlined into the delta rather than split into a
+	// separate fallback.
+	MinFallbackSize uint
+	// MaxChunkSize is the maximum size, in MiB, of a single delta part.
+	MaxChunkSize uint
+	// MaxBsdiffSize is the maximum size, in MiB, of content that will be
+	// considered for bsdiff; larger content falls back to raw copies.
+	MaxBsdiffSize uint
+	// InlineParts embeds delta parts into the superblock instead of
+	// writing them as separate files, trading a bigger superblock for
+	// fewer files to publish.
+	InlineParts bool
+	// Filename writes the detached superblock to this path instead of
+	// the repo's default `deltas/` layout.
+	Filename string
+}
+
+func (opts StaticDeltaOptions) toVariant() *C.GVariant {
+	builder := C.g_variant_builder_new(C._g_variant_type(C.CString("a{sv}")))
+
+	addUint32 := func(key string, value uint) {
+		if value == 0 {
+			return
+		}
+		v := C.g_variant_new_uint32(C.guint32(value))
+		k := C.CString(key)
+		defer C.free(unsafe.Pointer(k))
+		C._g_variant_builder_add_twoargs(builder, C.CString("{sv}"), k, v)
+	}
+
+	addString := func(key, value string) {
+		if value == "" {
+			return
+		}
+		cstr := C.CString(value)
+		v := C.g_variant_new_take_string((*C.gchar)(cstr))
+		k := C.CString(key)
+		defer C.free(unsafe.Pointer(k))
+		C._g_variant_builder_add_twoargs(builder, C.CString("{sv}"), k, v)
+	}
+
+	addBool := func(key string, value bool) {
+		if !value {
+			return
+		}
+		v := C.g_variant_new_boolean(C.gboolean(1))
+		k := C.CString(key)
+		defer C.free(unsafe.Pointer(k))
+		C._g_variant_builder_add_twoargs(builder, C.CString("{sv}"), k, v)
+	}
+
+	addUint32("min-fallback-size", opts.MinFallbackSize)
+	addUint32("max-chunk-size", opts.MaxChunkSize)
+	addUint32("max-bsdiff-size", opts.MaxBsdiffSize)
+	addBool("inline-parts", opts.InlineParts)
+	addString("filename", opts.Filename)
+
+	return C.g_variant_builder_end(builder)
+}
+
+// GenerateStaticDelta builds a static delta from the `from` commit to the
+// `to` commit, writing it into repo's `deltas/` directory (or opts.Filename
+// if set), the Go equivalent of `ostree static-delta generate`. `from` may
+// be empty to build a "from scratch" delta.
+func (repo *Repo) GenerateStaticDelta(from, to string, opts StaticDeltaOptions) error {
+	var cfrom *C.char
+	if from != "" {
+		cfrom = C.CString(from)
+		defer C.free(unsafe.Pointer(cfrom))
+	}
+
+	cto := C.CString(to)
+	defer C.free(unsafe.Pointer(cto))
+
+	coptions := opts.toVariant()
+
+	var cerr *C.GError
+	r := C.ostree_repo_static_delta_generate(repo.native(), C.OSTREE_STATIC_DELTA_GENERATE_OPT_MAJOR, cfrom, cto, nil, coptions, nil, &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
+
+// StaticDeltaInfo summarizes one delta stored in a repo's `deltas/`
+// directory, as returned by ListStaticDeltas.
+type StaticDeltaInfo struct {
+	// From is the source commit checksum, or "" for a from-scratch
+	// delta.
+	From string
+	// To is the target commit checksum.
+	To string
+}
+
+// ListStaticDeltas enumerates the static deltas published in repo, the
+// Go equivalent of `ostree static-delta list`.
+func (repo *Repo) ListStaticDeltas() ([]StaticDeltaInfo, error) {
+	names := C.ostree_repo_list_static_delta_names(repo.native(), nil, nil)
+	if names == nil {
+		return nil, nil
+	}
+	defer C.g_ptr_array_unref(names)
+
+	n := int(names.len)
+	deltas := make([]StaticDeltaInfo, 0, n)
+	for i := 0; i < n; i++ {
+		cname := (*C.char)(C.g_ptr_array_index(names, C.guint(i)))
+		name := C.GoString(cname)
+
+		from, to := "", name
+		if idx := indexByte(name, '-'); idx >= 0 {
+			from, to = name[:idx], name[idx+1:]
+		}
+		deltas = append(deltas, StaticDeltaInfo{From: from, To: to})
+	}
+	return deltas, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+func indexByte(s string, b byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == b {
+			return i
+		}
+	}
+	return -1
+}
+
+// ExecuteWrittenDelta applies a previously generated static delta
+// directly to repo, skipping the network entirely. deltaPath is the
+// delta-part directory libostree wrote; if superblockPath is set and is
+// not deltaPath's own `superblock` file, it is copied there before
+// execution, for callers that keep the detached superblock elsewhere.
+// The delta applied is the one the superblock describes; from and to are
+// not checked against it. This is the Go equivalent of
+// `ostree static-delta apply-offline`.
+func (repo *Repo) ExecuteWrittenDelta(from, to, superblockPath, deltaPath string) error {
+	if superblockPath != "" && superblockPath != deltaPath+"/superblock" {
+		if err := copyFile(superblockPath, deltaPath+"/superblock"); err != nil {
+			return err
+		}
+	}
+
+	cpath := C.CString(deltaPath)
+	defer C.free(unsafe.Pointer(cpath))
+
+	dir := C.g_file_new_for_path(cpath)
+	defer C.g_object_unref(C.gpointer(dir))
+
+	var cerr *C.GError
+	r := C.ostree_repo_static_delta_execute_offline(repo.native(), dir, C.FALSE, nil, &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
+
+// RegenerateSummary rebuilds repo's `summary` file, optionally merging in
+// additionalMetadata, the Go equivalent of `ostree summary --update`. Only
+// string values of additionalMetadata are written; entries with any other
+// value type are skipped. This
+// is the natural companion to GenerateStaticDelta since clients discover
+// available deltas through the summary.
+func (repo *Repo) RegenerateSummary(additionalMetadata map[string]interface{}) error {
+	builder := C.g_variant_builder_new(C._g_variant_type(C.CString("a{sv}")))
+	for k, v := range additionalMetadata {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		cstr := C.CString(s)
+		gv := C.g_variant_new_take_string((*C.gchar)(cstr))
+		ck := C.CString(k)
+		C._g_variant_builder_add_twoargs(builder, C.CString("{sv}"), ck, gv)
+		C.free(unsafe.Pointer(ck))
+	}
+	metadata := C.g_variant_builder_end(builder)
+
+	var cerr *C.GError
+	r := C.ostree_repo_regenerate_summary(repo.native(), metadata, nil, &cerr)
+	if !gobool(r) {
+		return generateError(cerr)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package otbuiltin
+
+import "testing"
+
+func TestIndexByte(t *testing.T) {
+	cases := []struct {
+		s    string
+		b    byte
+		want int
+	}{
+		{"abc-def", '-', 3},
+		{"noseparator", '-', -1},
+		{"", '-', -1},
+	}
+	for _, c := range cases {
+		if got := indexByte(c.s, c.b); got != c.want {
+			t.Errorf("indexByte(%q, %q) = %d, want %d", c.s, c.b, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package otbuiltin
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// newTestRepo shells out to the `ostree` CLI to initialize a fresh
+// bare-user-only repo, skipping the test if ostree isn't installed. The
+// binding itself has no repo-creation call (only OpenRepo), so tests set
+// one up the same way a user would from the command line.
+func newTestRepo(t *testing.T) (*Repo, string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("ostree"); err != nil {
+		t.Skip("ostree CLI not installed")
+	}
+
+	dir := t.TempDir()
+	repoPath := filepath.Join(dir, "repo")
+	cmd := exec.Command("ostree", "--repo="+repoPath, "init", "--mode=bare-user-only")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("ostree init: %v: %s", err, out)
+	}
+
+	repo, err := OpenRepo(repoPath)
+	if err != nil {
+		t.Fatalf("OpenRepo: %v", err)
+	}
+	return repo, dir
+}